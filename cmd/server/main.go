@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -38,18 +39,19 @@ func main() {
 	kafkaBundle := kafka.InitKafka()
 
 	// -----------------------------
+	// 4. Репозитории, сервисы, хэндлеры
 	// -----------------------------
-	// 4. Воркеры
+	bundle := bootstrap.InitBootstrap(cfg, dbConn, redisClient, kafkaBundle)
 	// -----------------------------
-	ctx := context.Background()
-	_ = workers.StartAllWorkers(ctx, redisClient, kafkaBundle)
-	// 5. Репозитории, сервисы, хэндлеры
+	// 5. Воркеры
 	// -----------------------------
-	bundle := bootstrap.InitBootstrap(dbConn, redisClient, kafkaBundle)
+	// Остановка отменяет и текущие запросы воркеров к провайдерам
+	_ = workers.StartAllWorkers(globalCtx, redisClient, kafkaBundle, bundle.Quota, bundle.Breakers, bundle.Repositories.RequestLogRepo, bundle.Alerts, bundle.Notifier, bundle.Updates)
+	go bundle.Stream.Run(globalCtx)
 	// -----------------------------
 	// 6. Cron jobs
 	// -----------------------------
-	bootstrap.StartCronJobs(globalCtx, bundle.Repositories.AdminRepo, kafkaBundle, cfg.PopularTopic)
+	bootstrap.StartCronJobs(globalCtx, cfg, redisClient, bundle.Repositories.AdminRepo, bundle.Commands, bundle.Popular, bundle.Digests, kafkaBundle, bundle.Quota)
 	// -----------------------------
 	// 7. Router
 	// -----------------------------
@@ -58,14 +60,50 @@ func main() {
 		bundle.Handlers.AdminHandler,
 		bundle.Handlers.WeatherHandler,
 		bundle.Handlers.ExchangeHandler,
+		bundle.Handlers.QuotaHandler,
+		bundle.Handlers.BreakerHandler,
+		bundle.Handlers.PopularHandler,
+		bundle.Handlers.MeHandler,
+		bundle.Handlers.AlertHandler,
+		bundle.Handlers.NotifyHandler,
+		bundle.Handlers.DigestHandler,
+		bundle.Handlers.StreamHandler,
+		bundle.Handlers.WSHandler,
+		bundle.Handlers.GraphQLHandler,
 		redisClient,
 	)
 
 	// -----------------------------
-	// 8. Запуск сервера с graceful shutdown
+	// 8. gRPC рядом с HTTP: те же сервисы, отдельный порт
+	// -----------------------------
+	grpcServer := bundle.GRPC.Register(redisClient)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("gRPC listen failed: %v", err)
+	}
+	go func() {
+		log.Printf("🚀 gRPC server starting on :%s", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Printf("❌ gRPC server failed: %v", err)
+		}
+	}()
+	go func() {
+		<-globalCtx.Done()
+		grpcServer.GracefulStop()
+		log.Println("✅ gRPC server stopped")
+	}()
+
+	// -----------------------------
+	// 9. Запуск сервера с graceful shutdown
 	// -----------------------------
 	port := cfg.Port
-	srv := &http.Server{Addr: ":" + cfg.Port, Handler: r}
+	// Контексты запросов наследуют globalCtx: при остановке незавершённые
+	// обращения к провайдерам отменяются, а не держат shutdown до таймаута
+	srv := &http.Server{
+		Addr:        ":" + cfg.Port,
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return globalCtx },
+	}
 	bootstrap.GracefulShutdown(srv, redisClient, kafkaBundle)
 
 	log.Printf("🚀 Server starting on :%s", port)
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+const (
+	fetchAttempts   = 2
+	fetchRetryDelay = 300 * time.Millisecond
+)
+
+// get выполняет GET к провайдеру и повторяет его при сетевых ошибках
+// и ответах 5xx. Тело ответа возвращается при любом статусе: разбор
+// ошибок у каждого провайдера свой. Ошибка возвращается, только если
+// ответа так и не получили. Отмена ctx прерывает и запрос, и повторы;
+// такая ошибка — не сбой провайдера и не *Error.
+func get(ctx context.Context, client *http.Client, provider, url string) (int, []byte, error) {
+	var status int
+	var body []byte
+
+	err := retry.Do(
+		func() error {
+			status, body = 0, nil
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return &Error{Provider: provider, Kind: ErrInvalidInput, Err: err}
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("%s: %w", provider, ctx.Err())
+				}
+				return &Error{Provider: provider, Kind: ErrUpstreamUnavailable, Err: err}
+			}
+			defer resp.Body.Close()
+
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				if ctx.Err() != nil {
+					return fmt.Errorf("%s: %w", provider, ctx.Err())
+				}
+				return &Error{Provider: provider, Kind: ErrUpstreamUnavailable, Status: resp.StatusCode, Err: err}
+			}
+			status, body = resp.StatusCode, data
+			if status >= 500 {
+				return &Error{Provider: provider, Kind: ErrUpstreamUnavailable, Status: status}
+			}
+			return nil
+		},
+		retry.Attempts(fetchAttempts),
+		retry.Delay(fetchRetryDelay),
+		retry.RetryIf(Retryable),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+	if status == 0 {
+		// Ответа не было: сеть или обрыв при чтении тела
+		return 0, nil, err
+	}
+	return status, body, nil
+}
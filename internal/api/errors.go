@@ -0,0 +1,71 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Виды ошибок провайдеров. Конкретная ошибка — *Error, проверять её вид
+// нужно через errors.Is(err, api.ErrNotFound) и т.п.
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrQuotaExceeded       = errors.New("upstream quota exceeded")
+	ErrUnauthorized        = errors.New("upstream rejected API key")
+)
+
+// Error — ошибка обращения к провайдеру
+type Error struct {
+	Provider string
+	Kind     error  // одна из Err* выше
+	Status   int    // HTTP-статус ответа провайдера, 0 если ответа не было
+	Message  string // сообщение провайдера
+	Err      error  // исходная ошибка (сеть, JSON), если есть
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Provider, e.Kind)
+	if e.Status != 0 {
+		msg += fmt.Sprintf(" (HTTP %d)", e.Status)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Retryable — стоит ли повторить запрос: только временные сбои провайдера,
+// повтор при неверном городе или исчерпанной квоте ничего не изменит
+func Retryable(err error) bool {
+	return errors.Is(err, ErrUpstreamUnavailable)
+}
+
+// kindForStatus — вид ошибки по HTTP-статусу, когда у провайдера нет своего кода
+func kindForStatus(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case status >= 500:
+		return ErrUpstreamUnavailable
+	case status >= 400:
+		return ErrInvalidInput
+	default:
+		return ErrUpstreamUnavailable
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -12,26 +13,48 @@ import (
 	"service-info/internal/models"
 )
 
+const providerFreeCurrency = "freecurrencyapi"
+
 var httpClientExchange = &http.Client{Timeout: 10 * time.Second}
 
-func FetchExchangeRate(base, target string) (*models.ExchangeRate, error) {
+func FetchExchangeRate(ctx context.Context, base, target string) (*models.ExchangeRate, error) {
 	apiKey := os.Getenv("FREECURRENCY_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("FREECURRENCY_API_KEY not set")
+		return nil, &Error{Provider: providerFreeCurrency, Kind: ErrUnauthorized, Message: "FREECURRENCY_API_KEY not set"}
 	}
 
+	baseURL := os.Getenv("FREECURRENCY_API_URL")
+	if baseURL == "" {
+		baseURL = "https://api.freecurrencyapi.com"
+	}
 	apiURL := fmt.Sprintf(
-		"https://api.freecurrencyapi.com/v1/latest?apikey=%s&base_currency=%s&currencies=%s",
+		"%s/v1/latest?apikey=%s&base_currency=%s&currencies=%s",
+		baseURL,
 		url.QueryEscape(apiKey),
 		url.QueryEscape(base),
 		url.QueryEscape(target),
 	)
 
-	resp, err := httpClientExchange.Get(apiURL)
+	status, body, err := get(ctx, httpClientExchange, providerFreeCurrency, apiURL)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+
+	// 422 — провайдер не знает валюту из запроса
+	if status == http.StatusUnprocessableEntity {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		return nil, &Error{Provider: providerFreeCurrency, Kind: ErrNotFound, Status: status, Message: errResp.Message}
+	}
+	if status != http.StatusOK {
+		var errResp struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &errResp)
+		return nil, &Error{Provider: providerFreeCurrency, Kind: kindForStatus(status), Status: status, Message: errResp.Message}
+	}
 
 	var result struct {
 		Data map[string]float64 `json:"data"`
@@ -40,13 +63,13 @@ func FetchExchangeRate(base, target string) (*models.ExchangeRate, error) {
 		} `json:"meta"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("JSON parse error: %w", err)
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, &Error{Provider: providerFreeCurrency, Kind: ErrUpstreamUnavailable, Status: status, Err: fmt.Errorf("JSON parse error: %w", err)}
 	}
 
 	rate, ok := result.Data[strings.ToUpper(target)]
 	if !ok {
-		return nil, fmt.Errorf("currency %s not found", target)
+		return nil, &Error{Provider: providerFreeCurrency, Kind: ErrNotFound, Message: "currency " + target + " not found"}
 	}
 
 	return &models.ExchangeRate{
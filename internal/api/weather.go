@@ -1,9 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -12,32 +12,63 @@ import (
 	"service-info/internal/models"
 )
 
+const providerWeatherAPI = "weatherapi"
+
+// DefaultWeatherLang — язык описания погоды, если клиент его не указал
+const DefaultWeatherLang = "ru"
+
 var httpClient = &http.Client{Timeout: 10 * time.Second}
 
-func FetchWeather(city string) (*models.Weather, error) {
+// Коды ошибок WeatherAPI: https://www.weatherapi.com/docs/#intro-error-codes
+var weatherAPIErrors = map[int]error{
+	1002: ErrUnauthorized,        // API key not provided
+	1003: ErrInvalidInput,        // Parameter 'q' not provided
+	1005: ErrInvalidInput,        // API request url is invalid
+	1006: ErrNotFound,            // No location found matching parameter 'q'
+	2006: ErrUnauthorized,        // API key provided is invalid
+	2007: ErrQuotaExceeded,       // API key has exceeded calls per month quota
+	2008: ErrUnauthorized,        // API key has been disabled
+	2009: ErrUnauthorized,        // API key does not have access to the resource
+	9000: ErrInvalidInput,        // Json body passed in bulk request is invalid
+	9001: ErrInvalidInput,        // Json body contains too many locations
+	9999: ErrUpstreamUnavailable, // Internal application error
+}
+
+// FetchWeather запрашивает текущую погоду; пустой lang — DefaultWeatherLang
+func FetchWeather(ctx context.Context, city, lang string) (*models.Weather, error) {
 	apiKey := os.Getenv("WEATHERAPI_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("WEATHERAPI_KEY not set")
+		return nil, &Error{Provider: providerWeatherAPI, Kind: ErrUnauthorized, Message: "WEATHERAPI_KEY not set"}
 	}
 
-	encodedCity := url.QueryEscape(city)
-	apiURL := fmt.Sprintf("https://api.weatherapi.com/v1/current.json?key=%s&q=%s&lang=ru", apiKey, encodedCity)
+	baseURL := os.Getenv("WEATHERAPI_URL")
+	if baseURL == "" {
+		baseURL = "https://api.weatherapi.com"
+	}
+	if lang == "" {
+		lang = DefaultWeatherLang
+	}
+	apiURL := fmt.Sprintf("%s/v1/current.json?key=%s&q=%s&lang=%s", baseURL, url.QueryEscape(apiKey), url.QueryEscape(city), url.QueryEscape(lang))
 
-	resp, err := httpClient.Get(apiURL)
+	status, body, err := get(ctx, httpClient, providerWeatherAPI, apiURL)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusOK {
+	if status != http.StatusOK {
 		var errResp struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
+			Error struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
 		}
 		_ = json.Unmarshal(body, &errResp)
-		return nil, fmt.Errorf("WeatherAPI %d: %s", resp.StatusCode, errResp.Message)
+
+		kind, ok := weatherAPIErrors[errResp.Error.Code]
+		if !ok {
+			kind = kindForStatus(status)
+		}
+		return nil, &Error{Provider: providerWeatherAPI, Kind: kind, Status: status, Message: errResp.Error.Message}
 	}
 
 	var apiResp struct {
@@ -59,11 +90,12 @@ func FetchWeather(city string) (*models.Weather, error) {
 	}
 
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("invalid JSON format: %w", err)
+		return nil, &Error{Provider: providerWeatherAPI, Kind: ErrUpstreamUnavailable, Status: status, Err: fmt.Errorf("invalid JSON format: %w", err)}
 	}
 
 	return &models.Weather{
 		City:         apiResp.Location.Name,
+		Lang:         lang,
 		Temp:         apiResp.Current.TempC,
 		FeelsLike:    apiResp.Current.FeelsLike,
 		Humidity:     apiResp.Current.Humidity,
@@ -0,0 +1,74 @@
+// Package apierror — единый формат ошибок HTTP API. Под /v1 ошибка — объект
+// {code, message, request_id, details}; старые маршруты без версии отвечают
+// {"error": message}, чтобы не ломать существующих клиентов.
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Машиночитаемые коды ошибок
+const (
+	CodeInvalidArgument       = "invalid_argument"
+	CodeUnauthorized          = "unauthorized"
+	CodeNotFound              = "not_found"
+	CodeConflict              = "conflict"
+	CodeUpstreamUnavailable   = "upstream_unavailable"
+	CodeUpstreamQuotaExceeded = "upstream_quota_exceeded"
+	CodeInternal              = "internal"
+)
+
+type Error struct {
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	RequestID string         `json:"request_id,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	versionKey   contextKey = "api_version"
+)
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func WithVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, versionKey, version)
+}
+
+func Version(ctx context.Context) string {
+	v, _ := ctx.Value(versionKey).(string)
+	return v
+}
+
+// Write отвечает ошибкой в формате версии API запроса
+func Write(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	WriteDetails(w, r, status, code, message, nil)
+}
+
+func WriteDetails(w http.ResponseWriter, r *http.Request, status int, code, message string, details map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if Version(r.Context()) == "" {
+		json.NewEncoder(w).Encode(map[string]string{"error": message})
+		return
+	}
+	json.NewEncoder(w).Encode(Error{
+		Code:      code,
+		Message:   message,
+		RequestID: RequestID(r.Context()),
+		Details:   details,
+	})
+}
@@ -4,12 +4,38 @@ import (
 	"context"
 	"time"
 
+	"service-info/internal/commands"
+	"service-info/internal/config"
 	"service-info/internal/cron"
 	"service-info/internal/kafka"
+	"service-info/internal/quota"
 	"service-info/internal/repositories"
+	"service-info/internal/services"
+
+	"github.com/redis/go-redis/v9"
 )
 
-func StartCronJobs(ctx context.Context, adminRepo *repositories.AdminRepository, kafkaBundle *kafka.KafkaBundle, popularTopic string) {
-	popularPublisher := cron.NewPopularPublisher(adminRepo, kafkaBundle.PopularProducer, popularTopic, 5*time.Minute)
-	go popularPublisher.Start(ctx)
+// StartCronJobs запускает cron-задачи только на реплике-лидере,
+// остальные реплики ждут и подхватывают лидерство при его потере.
+func StartCronJobs(
+	ctx context.Context,
+	cfg *config.Config,
+	redisClient *redis.Client,
+	adminRepo *repositories.AdminRepository,
+	commandRegistry *commands.Registry,
+	popularService *services.PopularService,
+	digestService *services.DigestService,
+	kafkaBundle *kafka.KafkaBundle,
+	quotaTracker *quota.Tracker,
+) {
+	popularPublisher := cron.NewPopularPublisher(popularService, kafkaBundle.PopularProducer, quotaTracker, cfg.PopularTopic, 5*time.Minute, cron.RefreshTiming{
+		Tick:    cfg.RefreshTick,
+		MinLead: cfg.RefreshMinLead,
+		MaxLead: cfg.RefreshMaxLead,
+	})
+	taskScheduler := cron.NewTaskScheduler(adminRepo, commandRegistry, kafkaBundle.PopularProducer, quotaTracker, 15*time.Second)
+	digestScheduler := cron.NewDigestScheduler(digestService, time.Minute)
+
+	elector := cron.NewLeaderElector(redisClient, "cron:leader", 15*time.Second)
+	go elector.Run(ctx, popularPublisher, taskScheduler, digestScheduler)
 }
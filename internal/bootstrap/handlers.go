@@ -2,11 +2,23 @@ package bootstrap
 
 import (
 	"database/sql"
+	"log"
+	"time"
 
+	"service-info/internal/api"
+	"service-info/internal/breaker"
+	"service-info/internal/commands"
+	"service-info/internal/config"
+	"service-info/internal/graph"
+	"service-info/internal/grpcserver"
 	"service-info/internal/handlers"
 	"service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/notify"
+	"service-info/internal/quota"
 	"service-info/internal/repositories"
 	"service-info/internal/services"
+	"service-info/internal/stream"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -16,17 +28,40 @@ type HandlersBundle struct {
 	WeatherHandler  *handlers.WeatherHandler
 	ExchangeHandler *handlers.ExchangeHandler
 	AdminHandler    *handlers.AdminHandler
+	QuotaHandler    *handlers.QuotaHandler
+	BreakerHandler  *handlers.BreakerHandler
+	PopularHandler  *handlers.PopularHandler
+	MeHandler       *handlers.MeHandler
+	AlertHandler    *handlers.AlertHandler
+	NotifyHandler   *handlers.NotificationHandler
+	DigestHandler   *handlers.DigestHandler
+	StreamHandler   *handlers.StreamHandler
+	WSHandler       *handlers.WSHandler
+	GraphQLHandler  *handlers.GraphQLHandler
 }
 
 type BootstrapBundle struct {
 	Handlers     *HandlersBundle
 	Repositories struct {
-		UserRepo  *repositories.UserRepository
-		AdminRepo *repositories.AdminRepository
+		UserRepo       *repositories.UserRepository
+		AdminRepo      *repositories.AdminRepository
+		RequestLogRepo *repositories.RequestLogRepository
+		PrefsRepo      *repositories.PreferencesRepository
 	}
+	Quota    *quota.Tracker
+	Breakers *breaker.Set
+	Commands *commands.Registry
+	Popular  *services.PopularService
+	Alerts   *services.AlertService
+	Notifier *notify.Dispatcher
+	Digests  *services.DigestService
+	Stream   *stream.Hub
+	Updates  *stream.Publisher
+	GRPC     *grpcserver.Server
 }
 
 func InitBootstrap(
+	cfg *config.Config,
 	db *sql.DB,
 	redisClient *redis.Client,
 	kafkaBundle *kafka.KafkaBundle,
@@ -37,6 +72,31 @@ func InitBootstrap(
 	// =====================
 	userRepo := repositories.NewUserRepository(db)
 	adminRepo := repositories.NewAdminRepository(db)
+	requestLogRepo := repositories.NewRequestLogRepository(db)
+	prefsRepo := repositories.NewPreferencesRepository(db)
+	alertRepo := repositories.NewAlertRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	digestRepo := repositories.NewDigestRepository(db)
+
+	// =====================
+	// Upstream quota
+	// =====================
+	quotaTracker := quota.NewTracker(redisClient, map[string]quota.Budget{
+		quota.ProviderWeatherAPI: {
+			Daily:   cfg.WeatherAPIDailyBudget,
+			Monthly: cfg.WeatherAPIMonthlyBudget,
+		},
+		quota.ProviderFreeCurrency: {
+			Daily:   cfg.FreeCurrencyDailyBudget,
+			Monthly: cfg.FreeCurrencyMonthlyBudget,
+		},
+	}, cfg.QuotaReservePercent)
+
+	// Выключатели размыкаются только на сбоях провайдера, не на «город не найден»
+	breakerSet := breaker.NewSet(map[string]breaker.Settings{
+		quota.ProviderWeatherAPI:   cfg.BreakerSettings(quota.ProviderWeatherAPI),
+		quota.ProviderFreeCurrency: cfg.BreakerSettings(quota.ProviderFreeCurrency),
+	}, api.Retryable)
 
 	// =====================
 	// Services (polymorphic)
@@ -45,13 +105,13 @@ func InitBootstrap(
 	weatherService := services.NewCacheService(
 		redisClient,
 		kafkaBundle.WeatherProducer,
-		services.WeatherFetcher{},
+		services.WeatherFetcher{Quota: quotaTracker, Breakers: breakerSet},
 	)
 
 	exchangeService := services.NewCacheService(
 		redisClient,
 		kafkaBundle.ExchangeProducer,
-		services.ExchangeFetcher{},
+		services.ExchangeFetcher{Quota: quotaTracker, Breakers: breakerSet},
 	)
 
 	userService := services.NewUserService(
@@ -61,6 +121,73 @@ func InitBootstrap(
 
 	adminService := services.NewAdminService(adminRepo)
 
+	alertService := services.NewAlertService(alertRepo, kafkaBundle.NotifyProducer)
+
+	// =====================
+	// Notification channels
+	// =====================
+	var channels []notify.Channel
+	if cfg.TelegramBotToken != "" {
+		channels = append(channels, notify.NewTelegram(cfg.TelegramAPIURL, cfg.TelegramBotToken))
+	}
+	// Webhook подписывается секретом самого канала, общий ключ не нужен
+	if cfg.WebhooksEnabled {
+		channels = append(channels, notify.NewWebhook())
+	}
+	if cfg.SMTPAddr != "" {
+		channels = append(channels, notify.NewEmail(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword))
+	}
+	dispatcher := notify.NewDispatcher(notificationRepo, uint(cfg.NotifyAttempts), cfg.NotifyRetryDelay, channels...)
+	notificationService := services.NewNotificationService(notificationRepo, dispatcher)
+
+	prefsService := services.NewPreferencesService(
+		prefsRepo,
+		redisClient,
+		weatherService,
+		exchangeService,
+		24*time.Hour,
+	)
+
+	digestService := services.NewDigestService(digestRepo, prefsService, kafkaBundle.NotifyProducer)
+
+	// =====================
+	// Admin commands: каждый источник данных регистрирует свою команду
+	// =====================
+	commandRegistry, err := commands.NewRegistry(
+		services.WeatherCommand,
+		services.ExchangeCommand,
+	)
+	if err != nil {
+		log.Fatalf("❌ Admin commands registration failed: %v", err)
+	}
+
+	requestRecorder := services.NewRequestRecorder(kafkaBundle.RequestProducer, commandRegistry)
+
+	// Политики популярности читаются один раз при старте: предупреждения
+	// о неверных значениях и итоговая политика видны в логе сразу
+	popularityPolicies := make(map[string]models.PopularityPolicy)
+	for _, cmd := range commandRegistry.Commands() {
+		policy := cfg.PopularityPolicy(cmd.TaskType)
+		log.Printf("📊 Popularity policy %s: window=%v top_n=%d min_count=%d half_life=%v",
+			cmd.TaskType, policy.Window, policy.TopN, policy.MinCount, policy.HalfLife)
+		popularityPolicies[cmd.TaskType] = policy
+	}
+
+	// Рейтинг живёт дольше интервала PopularPublisher, чтобы пережить смену лидера
+	popularService := services.NewPopularService(
+		redisClient,
+		adminRepo,
+		commandRegistry,
+		func(taskType string) models.PopularityPolicy { return popularityPolicies[taskType] },
+		30*time.Minute,
+	)
+
+	// =====================
+	// Обновления кэша для SSE: воркеры публикуют, хаб раздаёт клиентам
+	// =====================
+	streamHub := stream.NewHub(redisClient, 64)
+	updatesPublisher := stream.NewPublisher(redisClient)
+
 	// =====================
 	// Handlers
 	// =====================
@@ -69,23 +196,67 @@ func InitBootstrap(
 
 		WeatherHandler: handlers.NewWeatherHandler(
 			weatherService,
+			requestRecorder,
+			prefsService,
 		),
 
 		ExchangeHandler: handlers.NewExchangeHandler(
 			exchangeService,
+			requestRecorder,
+			prefsService,
 		),
 
-		AdminHandler: handlers.NewAdminHandler(adminService),
+		AdminHandler:   handlers.NewAdminHandler(adminService, commandRegistry),
+		QuotaHandler:   handlers.NewQuotaHandler(quotaTracker),
+		BreakerHandler: handlers.NewBreakerHandler(breakerSet),
+
+		PopularHandler: handlers.NewPopularHandler(popularService),
+		MeHandler:      handlers.NewMeHandler(prefsService),
+		AlertHandler:   handlers.NewAlertHandler(alertService),
+		NotifyHandler:  handlers.NewNotificationHandler(notificationService),
+		DigestHandler:  handlers.NewDigestHandler(digestService),
+		StreamHandler:  handlers.NewStreamHandler(streamHub),
+		WSHandler:      handlers.NewWSHandler(streamHub),
+		GraphQLHandler: handlers.NewGraphQLHandler(graph.NewSchema(
+			weatherService,
+			exchangeService,
+			prefsService,
+			popularService,
+			requestRecorder,
+			graph.Limits{MaxDepth: cfg.GraphQLMaxDepth, MaxComplexity: cfg.GraphQLMaxComplexity},
+		)),
 	}
 
 	return &BootstrapBundle{
 		Handlers: handlersBundle,
 		Repositories: struct {
-			UserRepo  *repositories.UserRepository
-			AdminRepo *repositories.AdminRepository
+			UserRepo       *repositories.UserRepository
+			AdminRepo      *repositories.AdminRepository
+			RequestLogRepo *repositories.RequestLogRepository
+			PrefsRepo      *repositories.PreferencesRepository
 		}{
-			UserRepo:  userRepo,
-			AdminRepo: adminRepo,
+			UserRepo:       userRepo,
+			AdminRepo:      adminRepo,
+			RequestLogRepo: requestLogRepo,
+			PrefsRepo:      prefsRepo,
 		},
+		Quota:    quotaTracker,
+		Breakers: breakerSet,
+		Commands: commandRegistry,
+		Popular:  popularService,
+		Alerts:   alertService,
+		Notifier: dispatcher,
+		Digests:  digestService,
+		Stream:   streamHub,
+		Updates:  updatesPublisher,
+		GRPC: grpcserver.NewServer(
+			weatherService,
+			exchangeService,
+			userService,
+			popularService,
+			prefsService,
+			requestRecorder,
+			streamHub,
+		),
 	}
 }
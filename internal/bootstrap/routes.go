@@ -2,8 +2,10 @@ package bootstrap
 
 import (
 	"net/http"
+	"service-info/internal/apierror"
 	"service-info/internal/handlers"
 	"service-info/internal/middleware"
+	"service-info/internal/openapi"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/redis/go-redis/v9"
@@ -14,24 +16,86 @@ func InitRoutes(
 	adminHandler *handlers.AdminHandler,
 	weatherHandler *handlers.WeatherHandler,
 	exchangeHandler *handlers.ExchangeHandler,
+	quotaHandler *handlers.QuotaHandler,
+	breakerHandler *handlers.BreakerHandler,
+	popularHandler *handlers.PopularHandler,
+	meHandler *handlers.MeHandler,
+	alertHandler *handlers.AlertHandler,
+	notifyHandler *handlers.NotificationHandler,
+	digestHandler *handlers.DigestHandler,
+	streamHandler *handlers.StreamHandler,
+	wsHandler *handlers.WSHandler,
+	graphqlHandler *handlers.GraphQLHandler,
 	redisClient *redis.Client,
 ) chi.Router {
 
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "route not found")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, http.StatusMethodNotAllowed, apierror.CodeInvalidArgument, "method not allowed")
+	})
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(200)
 		w.Write([]byte("OK"))
 	})
 
-	r.Post("/user", userHandler.CreateUser)
-	r.Post("/admin", adminHandler.CreatePopular)
+	r.Get("/openapi.json", openapi.ServeSpec)
+	r.Get("/docs", openapi.ServeDocs)
+
+	routes := func(r chi.Router) {
+		r.Post("/user", userHandler.CreateUser)
+		r.Get("/popular", popularHandler.GetPopular)
+		r.Post("/admin", adminHandler.CreatePopular)
+		r.Get("/admin/commands", adminHandler.ListCommands)
+		r.Get("/admin/quota", quotaHandler.GetQuota)
+		r.Get("/admin/breakers", breakerHandler.GetBreakers)
+		r.Route("/admin/tasks", func(r chi.Router) {
+			r.Get("/", adminHandler.ListTasks)
+			r.Delete("/", adminHandler.DeleteTasks)
+			r.Get("/{id}", adminHandler.GetTask)
+			r.Patch("/{id}", adminHandler.UpdateTask)
+			r.Delete("/{id}", adminHandler.DeleteTask)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.AuthRequired(redisClient))
+			r.Get("/weather", weatherHandler.GetWeather)
+			r.Post("/weather/batch", weatherHandler.GetWeatherBatch)
+			r.Get("/exchange", exchangeHandler.GetRate)
+			r.Get("/stream", streamHandler.Stream)
+			r.Get("/ws", wsHandler.Serve)
+			r.Post("/graphql", graphqlHandler.Query)
+			r.Get("/me/preferences", meHandler.GetPreferences)
+			r.Put("/me/preferences", meHandler.PutPreferences)
+			r.Get("/me/dashboard", meHandler.GetDashboard)
+			r.Get("/me/alerts/exchange", alertHandler.ListExchangeAlerts)
+			r.Post("/me/alerts/exchange", alertHandler.CreateExchangeAlert)
+			r.Delete("/me/alerts/exchange/{id}", alertHandler.DeleteExchangeAlert)
+			r.Get("/me/alerts/weather", alertHandler.ListWeatherAlerts)
+			r.Post("/me/alerts/weather", alertHandler.CreateWeatherAlert)
+			r.Delete("/me/alerts/weather/{id}", alertHandler.DeleteWeatherAlert)
+			r.Get("/me/alerts/events", alertHandler.ListEvents)
+			r.Get("/me/channels", notifyHandler.ListChannels)
+			r.Post("/me/channels", notifyHandler.AddChannel)
+			r.Delete("/me/channels/{id}", notifyHandler.DeleteChannel)
+			r.Get("/me/notifications", notifyHandler.ListDeliveries)
+			r.Get("/me/digest", digestHandler.GetDigest)
+			r.Put("/me/digest", digestHandler.PutDigest)
+			r.Delete("/me/digest", digestHandler.DeleteDigest)
+		})
+	}
 
-	r.Group(func(r chi.Router) {
-		r.Use(middleware.AuthRequired(redisClient))
-		r.Get("/weather", weatherHandler.GetWeather)
-		r.Get("/exchange", exchangeHandler.GetRate)
+	// /v1 — основное API с единым форматом ошибок; маршруты без версии
+	// оставлены для существующих клиентов и отвечают ошибками {"error": ...}
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(middleware.APIVersion("v1"))
+		routes(r)
 	})
+	r.Group(routes)
 
 	return r
 }
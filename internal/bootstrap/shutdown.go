@@ -27,11 +27,15 @@ func GracefulShutdown(srv *http.Server, redisClient *redis.Client, kafkaBundle *
 			kafkaBundle.UserConsumer.Stop()
 			kafkaBundle.ExchangeConsumer.Stop()
 			kafkaBundle.PopularConsumer.Stop()
+			kafkaBundle.RequestConsumer.Stop()
+			kafkaBundle.NotifyConsumer.Stop()
 
 			kafkaBundle.WeatherProducer.Close()
 			kafkaBundle.UserProducer.Close()
 			kafkaBundle.ExchangeProducer.Close()
 			kafkaBundle.PopularProducer.Close()
+			kafkaBundle.RequestProducer.Close()
+			kafkaBundle.NotifyProducer.Close()
 
 		}
 
@@ -0,0 +1,229 @@
+// Package breaker — автоматические выключатели для внешних провайдеров.
+// Пока провайдер лежит, запросы к нему отклоняются сразу, а не ждут таймаута.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	// StateClosed — провайдер работает, запросы проходят
+	StateClosed State = "closed"
+	// StateOpen — провайдер считается недоступным, запросы отклоняются
+	StateOpen State = "open"
+	// StateHalfOpen — пробные запросы после OpenTimeout
+	StateHalfOpen State = "half_open"
+)
+
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Settings — пороги выключателя; нулевые значения заменяются значениями по умолчанию
+type Settings struct {
+	FailureThreshold int           // подряд идущих сбоев до размыкания
+	OpenTimeout      time.Duration // сколько держать разомкнутым до пробы
+	HalfOpenRequests int           // успешных проб для замыкания
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.FailureThreshold < 1 {
+		s.FailureThreshold = 5
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 30 * time.Second
+	}
+	if s.HalfOpenRequests < 1 {
+		s.HalfOpenRequests = 1
+	}
+	return s
+}
+
+type Status struct {
+	Provider         string     `json:"provider"`
+	State            State      `json:"state"`
+	Failures         int        `json:"consecutive_failures"`
+	FailureThreshold int        `json:"failure_threshold"`
+	OpenedAt         *time.Time `json:"opened_at,omitempty"`
+	RetryAt          *time.Time `json:"retry_at,omitempty"`
+}
+
+type Breaker struct {
+	mu        sync.Mutex
+	name      string
+	settings  Settings
+	state     State
+	failures  int
+	successes int // успешные пробы в half-open
+	inFlight  int // пробы в half-open, ещё не завершённые
+	openedAt  time.Time
+	gen       uint64 // растёт при каждой смене состояния
+	now       func() time.Time
+}
+
+func New(name string, settings Settings) *Breaker {
+	return &Breaker{
+		name:     name,
+		settings: settings.withDefaults(),
+		state:    StateClosed,
+		now:      time.Now,
+	}
+}
+
+// Allow решает, пропускать ли запрос. После разрешения результат
+// передаётся в Done вместе с полученным поколением.
+func (b *Breaker) Allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.settings.OpenTimeout {
+			return 0, ErrOpen
+		}
+		b.setState(StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.settings.HalfOpenRequests {
+			return 0, ErrOpen
+		}
+		b.inFlight++
+	}
+	return b.gen, nil
+}
+
+// Done учитывает результат запроса. Запросы, начатые до смены
+// состояния, не учитываются — иначе опоздавший ответ из closed
+// сбил бы счёт проб в half-open.
+func (b *Breaker) Done(gen uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if gen != b.gen {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.settings.FailureThreshold {
+			b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		b.inFlight--
+		if failed {
+			b.failures++
+			b.setState(StateOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.settings.HalfOpenRequests {
+			b.setState(StateClosed)
+		}
+	}
+}
+
+// Cancel освобождает место пробы без учёта результата — запрос отменил
+// сам вызывающий, о провайдере это ничего не говорит
+func (b *Breaker) Cancel(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if gen == b.gen && b.state == StateHalfOpen {
+		b.inFlight--
+	}
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	log.Printf("🔌 Breaker %s: %s -> %s", b.name, b.state, state)
+	b.state = state
+	b.gen++
+	b.successes, b.inFlight = 0, 0
+	switch state {
+	case StateOpen:
+		b.openedAt = b.now()
+	case StateClosed:
+		b.failures = 0
+	}
+}
+
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{
+		Provider:         b.name,
+		State:            b.state,
+		Failures:         b.failures,
+		FailureThreshold: b.settings.FailureThreshold,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		retryAt := openedAt.Add(b.settings.OpenTimeout)
+		status.OpenedAt, status.RetryAt = &openedAt, &retryAt
+	}
+	return status
+}
+
+// Set — выключатели по провайдерам. Nil-набор ничего не ограничивает.
+type Set struct {
+	breakers  map[string]*Breaker
+	isFailure func(error) bool
+}
+
+// NewSet создаёт выключатель на каждого провайдера. isFailure отбирает
+// ошибки, которые говорят о сбое провайдера: «город не найден» — это
+// рабочий ответ и выключатель не размыкает.
+func NewSet(settings map[string]Settings, isFailure func(error) bool) *Set {
+	breakers := make(map[string]*Breaker, len(settings))
+	for provider, s := range settings {
+		breakers[provider] = New(provider, s)
+	}
+	return &Set{breakers: breakers, isFailure: isFailure}
+}
+
+// Do выполняет fn через выключатель провайдера; пока он разомкнут,
+// сразу возвращает ErrOpen. Результат не учитывается, только если
+// ctx вызывающего отменён или истёк: таймаут HTTP-клиента тоже
+// выглядит как context.DeadlineExceeded, но это сбой провайдера.
+func (s *Set) Do(ctx context.Context, provider string, fn func() error) error {
+	if s == nil || s.breakers[provider] == nil {
+		return fn()
+	}
+	b := s.breakers[provider]
+	gen, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if err != nil && ctx.Err() != nil {
+		b.Cancel(gen)
+		return err
+	}
+	b.Done(gen, err != nil && s.isFailure(err))
+	return err
+}
+
+// Providers возвращает состояние выключателей, отсортированное по провайдеру
+func (s *Set) Providers() []Status {
+	if s == nil {
+		return []Status{}
+	}
+	statuses := make([]Status, 0, len(s.breakers))
+	for _, b := range s.breakers {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Provider < statuses[j].Provider })
+	return statuses
+}
@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"service-info/internal/models"
+)
+
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrInvalidArgs    = errors.New("invalid arguments")
+)
+
+// ArgSpec описывает позиционный аргумент команды
+type ArgSpec struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	// Key — аргумент входит в идентичность запроса: по нему строится ключ кэша
+	// и группируется популярность
+	Key  bool   `json:"key"`
+	Help string `json:"help"`
+
+	Normalize func(string) string `json:"-"`
+	Validate  func(string) error  `json:"-"`
+}
+
+// Command — команда админки, которой источник данных описывает свои задачи
+type Command struct {
+	Name        string    `json:"name"`
+	TaskType    string    `json:"type"`
+	Description string    `json:"description"`
+	Args        []ArgSpec `json:"args"`
+
+	// CacheKey строит ключ Redis по нормализованным аргументам
+	CacheKey func(args models.TaskArgs) string `json:"-"`
+}
+
+func (c Command) Usage() string {
+	var b strings.Builder
+	b.WriteString(c.Name)
+	for _, a := range c.Args {
+		if a.Required {
+			b.WriteString(" <" + a.Name + ">")
+		} else {
+			b.WriteString(" [" + a.Name + "]")
+		}
+	}
+	return b.String()
+}
+
+// Parse раскладывает позиционные аргументы, нормализует и валидирует их
+func (c Command) Parse(fields []string) (models.TaskArgs, error) {
+	if len(fields) > len(c.Args) {
+		return nil, fmt.Errorf("%w: usage: %s", ErrInvalidArgs, c.Usage())
+	}
+
+	args := make(models.TaskArgs)
+	for i, spec := range c.Args {
+		if i >= len(fields) {
+			if spec.Required {
+				return nil, fmt.Errorf("%w: usage: %s", ErrInvalidArgs, c.Usage())
+			}
+			continue
+		}
+
+		value := strings.TrimSpace(fields[i])
+		if spec.Normalize != nil {
+			value = spec.Normalize(value)
+		}
+		if spec.Validate != nil {
+			if err := spec.Validate(value); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArgs, spec.Name, err)
+			}
+		}
+		args[spec.Name] = value
+	}
+	return args, nil
+}
+
+// KeyArgs — имена аргументов, определяющих идентичность запроса
+func (c Command) KeyArgs() []string {
+	var keys []string
+	for _, a := range c.Args {
+		if a.Key {
+			keys = append(keys, a.Name)
+		}
+	}
+	return keys
+}
+
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]Command
+	byType map[string]Command
+}
+
+// NewRegistry регистрирует команды; ошибка — первая команда, которую
+// зарегистрировать не удалось (дубликат имени или типа, /help или пустой тип)
+func NewRegistry(cmds ...Command) (*Registry, error) {
+	r := &Registry{
+		byName: make(map[string]Command),
+		byType: make(map[string]Command),
+	}
+	for _, cmd := range cmds {
+		if err := r.Register(cmd); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Registry) Register(cmd Command) error {
+	if !strings.HasPrefix(cmd.Name, "/") || cmd.TaskType == "" {
+		return fmt.Errorf("command %q: name must start with / and type is required", cmd.Name)
+	}
+	if cmd.Name == "/help" {
+		return fmt.Errorf("command /help is reserved")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byName[cmd.Name]; ok {
+		return fmt.Errorf("command %s already registered", cmd.Name)
+	}
+	// По типу задачи планировщик и популярность находят команду — он должен быть однозначным
+	if other, ok := r.byType[cmd.TaskType]; ok {
+		return fmt.Errorf("command %s: type %q already registered by %s", cmd.Name, cmd.TaskType, other.Name)
+	}
+	r.byName[cmd.Name] = cmd
+	r.byType[cmd.TaskType] = cmd
+	return nil
+}
+
+func (r *Registry) Lookup(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmd, ok := r.byName[name]
+	return cmd, ok
+}
+
+func (r *Registry) ByType(taskType string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmd, ok := r.byType[taskType]
+	return cmd, ok
+}
+
+// Commands возвращает зарегистрированные команды в алфавитном порядке
+func (r *Registry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cmds := make([]Command, 0, len(r.byName))
+	for _, cmd := range r.byName {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+	return cmds
+}
+
+// ParseTask превращает текст вида "/weather Moscow" в задачу
+func (r *Registry) ParseTask(text string) (models.Task, error) {
+	parts := strings.Fields(text)
+	if len(parts) == 0 {
+		return models.Task{}, fmt.Errorf("%w: empty command", ErrInvalidArgs)
+	}
+
+	cmd, ok := r.Lookup(parts[0])
+	if !ok {
+		return models.Task{}, fmt.Errorf("%w: %s", ErrUnknownCommand, parts[0])
+	}
+
+	args, err := cmd.Parse(parts[1:])
+	if err != nil {
+		return models.Task{}, err
+	}
+
+	return models.Task{
+		Title:     cmd.TaskType,
+		Args:      args,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Help — справка по всем командам или по одной ("/help weather")
+func (r *Registry) Help(topic string) (string, error) {
+	if topic != "" {
+		if !strings.HasPrefix(topic, "/") {
+			topic = "/" + topic
+		}
+		cmd, ok := r.Lookup(topic)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownCommand, topic)
+		}
+		return commandHelp(cmd), nil
+	}
+
+	var b strings.Builder
+	b.WriteString("Available commands:\n")
+	for _, cmd := range r.Commands() {
+		b.WriteString("  " + cmd.Usage() + " — " + cmd.Description + "\n")
+	}
+	b.WriteString("  /help [command] — show this help")
+	return b.String(), nil
+}
+
+func commandHelp(cmd Command) string {
+	var b strings.Builder
+	b.WriteString(cmd.Usage() + " — " + cmd.Description)
+	for _, a := range cmd.Args {
+		b.WriteString("\n  " + a.Name + ": " + a.Help)
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+func Lower(s string) string { return strings.ToLower(s) }
+
+func Upper(s string) string { return strings.ToUpper(s) }
+
+func NotEmpty(s string) error {
+	if s == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return nil
+}
+
+// Letters возвращает валидатор строки ровно из n латинских букв (коды валют, языков)
+func Letters(n int) func(string) error {
+	return func(s string) error {
+		if len(s) != n {
+			return fmt.Errorf("must be %d letters", n)
+		}
+		for _, r := range s {
+			if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+				return fmt.Errorf("must be %d latin letters", n)
+			}
+		}
+		return nil
+	}
+}
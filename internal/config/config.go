@@ -3,6 +3,12 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-info/internal/breaker"
+	"service-info/internal/models"
 
 	"github.com/joho/godotenv"
 )
@@ -14,9 +20,39 @@ type Config struct {
 	UserTopic       string
 	ExchangeTopic   string
 	PopularTopic    string
+	RequestTopic    string
+	NotifyTopic     string
 	WeatherAPIKey   string
 	FreeCurrencyKey string
 	Port            string
+	GRPCPort        string
+
+	// Бюджеты вызовов внешних API (0 — без ограничений)
+	WeatherAPIDailyBudget     int
+	WeatherAPIMonthlyBudget   int
+	FreeCurrencyDailyBudget   int
+	FreeCurrencyMonthlyBudget int
+	QuotaReservePercent       int
+
+	// Адаптивное обновление популярных ключей
+	RefreshTick    time.Duration
+	RefreshMinLead time.Duration
+	RefreshMaxLead time.Duration
+
+	// Каналы доставки уведомлений; канал без настроек не подключается
+	TelegramAPIURL   string
+	TelegramBotToken string
+	WebhooksEnabled  bool
+	SMTPAddr         string
+	SMTPFrom         string
+	SMTPUsername     string
+	SMTPPassword     string
+	NotifyAttempts   int
+	NotifyRetryDelay time.Duration
+
+	// Лимиты GraphQL-запроса
+	GraphQLMaxDepth      int
+	GraphQLMaxComplexity int
 }
 
 func Load() *Config {
@@ -31,9 +67,35 @@ func Load() *Config {
 		UserTopic:       getEnv("USER_KAFKA_TOPIC", "user-events"),
 		ExchangeTopic:   getEnv("EXCHANGE_KAFKA_TOPIC", "exchange-updates"),
 		PopularTopic:    "popular-requests",
+		RequestTopic:    getEnv("REQUEST_EVENTS_KAFKA_TOPIC", "request-events"),
+		NotifyTopic:     getEnv("NOTIFICATIONS_KAFKA_TOPIC", "notifications"),
 		WeatherAPIKey:   os.Getenv("WEATHERAPI_KEY"),
 		FreeCurrencyKey: os.Getenv("FREECURRENCY_API_KEY"),
 		Port:            getEnv("PORT", "8080"),
+		GRPCPort:        getEnv("GRPC_PORT", "9090"),
+
+		WeatherAPIDailyBudget:     getEnvInt("WEATHERAPI_DAILY_BUDGET", 0),
+		WeatherAPIMonthlyBudget:   getEnvInt("WEATHERAPI_MONTHLY_BUDGET", 1000000),
+		FreeCurrencyDailyBudget:   getEnvInt("FREECURRENCY_DAILY_BUDGET", 0),
+		FreeCurrencyMonthlyBudget: getEnvInt("FREECURRENCY_MONTHLY_BUDGET", 5000),
+		QuotaReservePercent:       getEnvInt("QUOTA_RESERVE_PERCENT", 20),
+
+		RefreshTick:    getEnvDuration("POPULAR_REFRESH_TICK", 10*time.Second),
+		RefreshMinLead: getEnvDuration("POPULAR_REFRESH_MIN_LEAD", 15*time.Second),
+		RefreshMaxLead: getEnvDuration("POPULAR_REFRESH_MAX_LEAD", 2*time.Minute),
+
+		TelegramAPIURL:   getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+		TelegramBotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
+		WebhooksEnabled:  getEnv("WEBHOOKS_ENABLED", "true") == "true",
+		SMTPAddr:         os.Getenv("SMTP_ADDR"),
+		SMTPFrom:         getEnv("SMTP_FROM", "service-info@localhost"),
+		SMTPUsername:     os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
+		NotifyAttempts:   getEnvInt("NOTIFY_ATTEMPTS", 4),
+		NotifyRetryDelay: getEnvDuration("NOTIFY_RETRY_DELAY", time.Second),
+
+		GraphQLMaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 6),
+		GraphQLMaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 50),
 	}
 }
 
@@ -43,3 +105,73 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("⚠️ %s=%q is not a number, using %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("⚠️ %s=%q is not a duration, using %v", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
+// PopularityPolicy читает политику популярности типа задач из переменных
+// POPULAR_<TYPE>_WINDOW, _TOP_N, _MIN_COUNT, _HALF_LIFE; без них действуют
+// общие POPULAR_* и, в последнюю очередь, значения по умолчанию.
+// Недопустимые значения заменяются ближайшими допустимыми с предупреждением.
+func (c *Config) PopularityPolicy(taskType string) models.PopularityPolicy {
+	prefix := "POPULAR_" + strings.ToUpper(taskType) + "_"
+	policy := models.PopularityPolicy{
+		Window:   getEnvDuration(prefix+"WINDOW", getEnvDuration("POPULAR_WINDOW", 24*time.Hour)),
+		TopN:     getEnvInt(prefix+"TOP_N", getEnvInt("POPULAR_TOP_N", 5)),
+		MinCount: getEnvInt(prefix+"MIN_COUNT", getEnvInt("POPULAR_MIN_COUNT", 1)),
+		HalfLife: getEnvDuration(prefix+"HALF_LIFE", getEnvDuration("POPULAR_HALF_LIFE", 0)),
+	}
+
+	if policy.Window <= 0 {
+		log.Printf("⚠️ Popularity %s: window=%v must be positive, using 24h", taskType, policy.Window)
+		policy.Window = 24 * time.Hour
+	}
+	if policy.TopN < 1 {
+		log.Printf("⚠️ Popularity %s: top_n=%d must be at least 1, using 1", taskType, policy.TopN)
+		policy.TopN = 1
+	}
+	if policy.MinCount < 1 {
+		log.Printf("⚠️ Popularity %s: min_count=%d must be at least 1, using 1", taskType, policy.MinCount)
+		policy.MinCount = 1
+	}
+	if policy.HalfLife < 0 {
+		log.Printf("⚠️ Popularity %s: half_life=%v must not be negative, decay disabled", taskType, policy.HalfLife)
+		policy.HalfLife = 0
+	}
+	return policy
+}
+
+// BreakerSettings читает пороги выключателя провайдера из переменных
+// BREAKER_<PROVIDER>_FAILURE_THRESHOLD, _OPEN_TIMEOUT, _HALF_OPEN_REQUESTS;
+// без них действуют общие BREAKER_* и значения по умолчанию.
+func (c *Config) BreakerSettings(provider string) breaker.Settings {
+	prefix := "BREAKER_" + strings.ToUpper(provider) + "_"
+	return breaker.Settings{
+		FailureThreshold: getEnvInt(prefix+"FAILURE_THRESHOLD", getEnvInt("BREAKER_FAILURE_THRESHOLD", 5)),
+		OpenTimeout:      getEnvDuration(prefix+"OPEN_TIMEOUT", getEnvDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second)),
+		HalfOpenRequests: getEnvInt(prefix+"HALF_OPEN_REQUESTS", getEnvInt("BREAKER_HALF_OPEN_REQUESTS", 1)),
+	}
+}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+)
+
+// DigestScheduler раз в interval отправляет утренние сводки, время которых
+// наступило в часовом поясе пользователя
+type DigestScheduler struct {
+	digests   *services.DigestService
+	interval  time.Duration
+	batchSize int
+}
+
+func NewDigestScheduler(digests *services.DigestService, interval time.Duration) *DigestScheduler {
+	return &DigestScheduler{
+		digests:   digests,
+		interval:  interval,
+		batchSize: 200,
+	}
+}
+
+func (s *DigestScheduler) Start(ctx context.Context) {
+	log.Printf("🕗 DigestScheduler started (interval: %v)", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.RunOnce(ctx); err != nil {
+				log.Printf("DigestScheduler iteration failed: %v", err)
+			}
+
+		case <-ctx.Done():
+			log.Println("DigestScheduler stopped")
+			return
+		}
+	}
+}
+
+func (s *DigestScheduler) RunOnce(ctx context.Context) error {
+	due, err := s.digests.Due(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range due {
+		// Лидерство проверяем перед каждой отправкой: сводки — побочный эффект
+		if !stillLeader(ctx) {
+			return errNotLeader
+		}
+		sent, err := s.digests.Send(ctx, leaderTerm(ctx), d)
+		if errors.Is(err, repositories.ErrStaleTerm) {
+			return errNotLeader
+		}
+		if err != nil {
+			log.Printf("Digest for %d failed: %v", d.UserID, err)
+			continue
+		}
+		if sent {
+			log.Printf("☀️ Digest for %d (%s) published", d.UserID, d.LastSentDate)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Job — фоновая задача, работающая до отмены контекста
+type Job interface {
+	Start(ctx context.Context)
+}
+
+var errNotLeader = errors.New("cron leadership lost")
+
+type leaseKey struct{}
+
+// lease — срок лидерства, в котором работают задачи
+type lease struct {
+	term  int64
+	holds func(context.Context) bool
+}
+
+func withLease(ctx context.Context, term int64, holds func(context.Context) bool) context.Context {
+	return context.WithValue(ctx, leaseKey{}, lease{term: term, holds: holds})
+}
+
+// stillLeader проверяет по Redis, что аренда ещё наша, — дешёвая проверка
+// перед пачкой побочных эффектов. Между ней и действием аренда может
+// истечь, поэтому записи в БД дополнительно защищены сроком leaderTerm.
+// Вне LeaderElector (например, в тестах) задачи считаются лидером.
+func stillLeader(ctx context.Context) bool {
+	l, ok := ctx.Value(leaseKey{}).(lease)
+	return !ok || l.holds(ctx)
+}
+
+// leaderTerm — fencing-токен для записей в БД: ClaimDue, RecordRun и
+// Claim сводок отклоняют срок старше уже записанного (ErrStaleTerm),
+// так что бывший лидер ничего не запустит после смены лидерства.
+// 0 — вне LeaderElector, без проверки.
+func leaderTerm(ctx context.Context) int64 {
+	l, _ := ctx.Value(leaseKey{}).(lease)
+	return l.term
+}
+
+// acquireLeaseScript захватывает свободную аренду. Значение аренды —
+// "<instance>:<term>", term растёт с каждым новым лидерством: по нему
+// реплика не спутает свою прошлую аренду с текущей, а Postgres отличит
+// записи бывшего лидера. Счётчик term не имеет TTL и не должен сбрасываться:
+// после сброса новые сроки окажутся меньше записанного в leader_terms.
+var acquireLeaseScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('SET', KEYS[1], ARGV[1] .. ':' .. token, 'PX', ARGV[2])
+return token
+`)
+
+// renewLeaseScript продлевает аренду, только если она всё ещё наша
+var renewLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LeaderElector выбирает одну реплику, на которой работают cron-задачи.
+// Лидер держит аренду в Redis и продлевает её каждые ttl/3; если продлить
+// не удаётся, задачи останавливаются за ttl/3 до истечения аренды, а по
+// истечении ttl её забирает другая реплика.
+type LeaderElector struct {
+	redis    *redis.Client
+	key      string
+	instance string
+	ttl      time.Duration
+}
+
+func NewLeaderElector(redisClient *redis.Client, key string, ttl time.Duration) *LeaderElector {
+	host, _ := os.Hostname()
+	return &LeaderElector{
+		redis:    redisClient,
+		key:      key,
+		instance: host + "-" + strconv.Itoa(os.Getpid()) + "-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		ttl:      ttl,
+	}
+}
+
+// Run борется за лидерство до отмены ctx и, пока реплика лидер, выполняет jobs
+func (e *LeaderElector) Run(ctx context.Context, jobs ...Job) {
+	log.Printf("🗳 LeaderElector started (key: %s, instance: %s)", e.key, e.instance)
+
+	retry := time.NewTicker(e.ttl / 3)
+	defer retry.Stop()
+
+	for {
+		// Аренда отсчитывается не раньше отправки запроса
+		sent := time.Now()
+		token, err := e.acquire(ctx)
+		if err != nil {
+			log.Printf("Leader lease acquire failed: %v", err)
+		}
+		if token > 0 {
+			e.lead(ctx, token, sent, jobs)
+		}
+
+		select {
+		case <-retry.C:
+		case <-ctx.Done():
+			log.Println("LeaderElector stopped")
+			return
+		}
+	}
+}
+
+func (e *LeaderElector) lead(ctx context.Context, token int64, acquiredAt time.Time, jobs []Job) {
+	log.Printf("👑 Became cron leader (term %d)", token)
+
+	leaderCtx, cancel := context.WithCancel(withLease(ctx, token, func(ctx context.Context) bool {
+		return e.Holds(ctx, token)
+	}))
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			job.Start(leaderCtx)
+		}(job)
+	}
+
+	e.keepAlive(leaderCtx, token, acquiredAt)
+	cancel()
+	wg.Wait()
+
+	// Отдаём аренду сразу, чтобы другая реплика не ждала истечения ttl
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer releaseCancel()
+	if err := releaseLeaseScript.Run(releaseCtx, e.redis, []string{e.key}, e.value(token)).Err(); err != nil {
+		log.Printf("Leader lease release failed: %v", err)
+	}
+	log.Printf("Cron leadership lost (term %d)", token)
+}
+
+// keepAlive продлевает аренду, пока это удаётся. Аренда действует ttl с
+// момента отправки последнего удачного запроса (acquiredAt для первого);
+// если продлить её не вышло, выходим за ttl/3 до этого срока, чтобы задачи
+// остановились раньше, чем аренду сможет забрать другая реплика.
+func (e *LeaderElector) keepAlive(ctx context.Context, token int64, acquiredAt time.Time) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	stepDownAt := acquiredAt.Add(e.ttl - e.ttl/3)
+	stepDown := time.NewTimer(time.Until(stepDownAt))
+	defer stepDown.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stepDown.C:
+			log.Printf("Leader lease (term %d) not renewed in time", token)
+			return
+		case <-ticker.C:
+			sent := time.Now()
+			renewCtx, cancel := context.WithDeadline(ctx, stepDownAt)
+			ok, err := renewLeaseScript.Run(renewCtx, e.redis, []string{e.key}, e.value(token), e.ttl.Milliseconds()).Int()
+			cancel()
+			switch {
+			case err != nil:
+				log.Printf("Leader lease renew failed: %v", err)
+			case ok == 0:
+				log.Printf("Leader lease (term %d) taken over", token)
+				return
+			default:
+				stepDownAt = sent.Add(e.ttl - e.ttl/3)
+				stepDown.Reset(time.Until(stepDownAt))
+			}
+		}
+	}
+}
+
+func (e *LeaderElector) acquire(ctx context.Context) (int64, error) {
+	token, err := acquireLeaseScript.Run(ctx, e.redis,
+		[]string{e.key, e.key + ":term"},
+		e.instance, e.ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("leader lease %s: %w", e.key, err)
+	}
+	return token, nil
+}
+
+// Holds проверяет по Redis, что аренда этого срока всё ещё у этой реплики
+func (e *LeaderElector) Holds(ctx context.Context, token int64) bool {
+	val, err := e.redis.Get(ctx, e.key).Result()
+	if err != nil {
+		return false
+	}
+	return token > 0 && val == e.value(token)
+}
+
+func (e *LeaderElector) value(token int64) string {
+	return e.instance + ":" + strconv.FormatInt(token, 10)
+}
@@ -8,36 +8,76 @@ import (
 
 	messaging "service-info/internal/kafka"
 	"service-info/internal/models"
-	"service-info/internal/repositories"
+	"service-info/internal/quota"
+	"service-info/internal/services"
 )
 
+// RefreshTiming управляет адаптивным обновлением популярных ключей.
+// Ключ обновляется, когда до истечения его TTL в Redis остаётся меньше lead;
+// lead растёт с популярностью от MinLead до MaxLead, поэтому самые
+// горячие ключи обновляются заранее и никогда не «остывают».
+type RefreshTiming struct {
+	Tick    time.Duration
+	MinLead time.Duration
+	MaxLead time.Duration
+}
+
+func (t RefreshTiming) withDefaults() RefreshTiming {
+	if t.Tick <= 0 {
+		t.Tick = 10 * time.Second
+	}
+	if t.MinLead <= 0 {
+		t.MinLead = 15 * time.Second
+	}
+	if t.MaxLead < t.MinLead {
+		t.MaxLead = 8 * t.MinLead
+	}
+	return t
+}
+
 type PopularPublisher struct {
-	adminRepo *repositories.AdminRepository
-	producer  *messaging.Producer
-	topic     string
-	interval  time.Duration
+	popular  *services.PopularService
+	producer messaging.SyncProducer
+	quota    *quota.Tracker
+	topic    string
+	interval time.Duration
+	timing   RefreshTiming
+
+	hot      []models.PopularRequest
+	rankedAt time.Time
 }
 
+// NewPopularPublisher: interval — как часто пересчитывать рейтинг,
+// timing — как часто и насколько заранее обновлять ключи из рейтинга
 func NewPopularPublisher(
-	adminRepo *repositories.AdminRepository,
-	producer *messaging.Producer,
+	popular *services.PopularService,
+	producer messaging.SyncProducer,
+	quotaTracker *quota.Tracker,
 	topic string,
 	interval time.Duration,
+	timing RefreshTiming,
 ) *PopularPublisher {
 	return &PopularPublisher{
-		adminRepo: adminRepo,
-		producer:  producer,
-		topic:     topic,
-		interval:  interval,
+		popular:  popular,
+		producer: producer,
+		quota:    quotaTracker,
+		topic:    topic,
+		interval: interval,
+		timing:   timing.withDefaults(),
 	}
 }
 
 func (p *PopularPublisher) Start(ctx context.Context) {
-	log.Printf("🕗 PopularPublisher started (interval: %v, topic: %s)", p.interval, p.topic)
+	log.Printf("🕗 PopularPublisher started (rank interval: %v, refresh tick: %v, topic: %s)", p.interval, p.timing.Tick, p.topic)
 
-	ticker := time.NewTicker(p.interval)
+	ticker := time.NewTicker(p.timing.Tick)
 	defer ticker.Stop()
 
+	// Первый прогон сразу, чтобы рейтинг в /popular появился без ожидания тика
+	if err := p.RunOnce(ctx); err != nil {
+		log.Printf("PopularPublisher iteration failed: %v", err)
+	}
+
 	for {
 		select {
 		case <-ticker.C:
@@ -52,46 +92,100 @@ func (p *PopularPublisher) Start(ctx context.Context) {
 	}
 }
 
+// RunOnce пересчитывает рейтинг, если он устарел, и запрашивает обновление
+// тех популярных ключей, которые скоро истекут
 func (p *PopularPublisher) RunOnce(ctx context.Context) error {
-	top, err := p.adminRepo.GetTopRequests(ctx)
+	if !stillLeader(ctx) {
+		return errNotLeader
+	}
+
+	if p.rankedAt.IsZero() || time.Since(p.rankedAt) >= p.interval {
+		p.popular.RefreshSnapshots(ctx)
+
+		top, err := p.popular.Rank(ctx, 0)
+		if err != nil {
+			return err
+		}
+		p.hot, p.rankedAt = top, time.Now()
+	}
+
+	if len(p.hot) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(p.hot))
+	for i, req := range p.hot {
+		keys[i] = p.popular.CacheKey(req)
+	}
+	ttls, err := p.popular.TTLs(ctx, keys)
 	if err != nil {
 		return err
 	}
 
-	if len(top) == 0 {
-		log.Println("No popular requests found")
-		return nil
+	maxScore := make(map[string]float64)
+	for _, req := range p.hot {
+		if req.Score > maxScore[req.Type] {
+			maxScore[req.Type] = req.Score
+		}
 	}
 
-	log.Printf("Publishing %d popular requests to Kafka...", len(top))
+	// Префетч не обязателен: при низком остатке бюджета откладываем его до следующего тика
+	allowed := make(map[string]bool)
+	for i, req := range p.hot {
+		if !p.timing.Due(ttls[i], req.Score, maxScore[req.Type]) {
+			continue
+		}
 
-	for _, req := range top {
-		value, err := json.Marshal(req)
-		if err != nil {
-			log.Printf("Marshal error: %v", err)
+		provider := quota.TaskProviders[req.Type]
+		ok, checked := allowed[provider]
+		if !checked {
+			ok = p.quota.Allows(ctx, provider, quota.PriorityBackground)
+			allowed[provider] = ok
+		}
+		if !ok {
+			log.Printf("⏸ Quota low for %s, prefetch of %s deferred", provider, keys[i])
 			continue
 		}
 
-		key := p.generateKey(req)
-		if err := p.producer.Publish(key, value); err != nil {
-			log.Printf("Kafka publish failed (key=%s): %v", string(key), err)
-		} else {
-			log.Printf("Published: %s", string(key))
+		// Пока команда в очереди, ключ не переотправляем
+		if fresh, err := p.popular.MarkRefreshPending(ctx, keys[i], p.timing.MinLead); err != nil || !fresh {
+			continue
 		}
+
+		p.publish(req, keys[i])
 	}
 
 	return nil
 }
 
-func (p *PopularPublisher) generateKey(req models.PopularRequest) []byte {
-	switch req.Type {
-	case "weather":
-		city := req.Args["city"]
-		return []byte("weather:" + city)
-	case "exchange":
-		base, target := req.Args["base"], req.Args["target"]
-		return []byte("exchange:" + base + "_" + target)
-	default:
-		return []byte("unknown")
+// Due решает, пора ли обновлять ключ: отсутствующий — сразу, вечный — никогда,
+// иначе когда TTL меньше lead, пропорционального популярности
+func (t RefreshTiming) Due(ttl time.Duration, score, maxScore float64) bool {
+	switch {
+	case ttl == -1:
+		return false
+	case ttl < 0:
+		return true
+	}
+
+	weight := 1.0
+	if maxScore > 0 {
+		weight = score / maxScore
+	}
+	lead := t.MinLead + time.Duration(weight*float64(t.MaxLead-t.MinLead))
+	return ttl <= lead
+}
+
+func (p *PopularPublisher) publish(req models.PopularRequest, cacheKey string) {
+	value, err := json.Marshal(models.PopularRequest{Type: req.Type, Args: req.Args})
+	if err != nil {
+		log.Printf("Marshal error: %v", err)
+		return
+	}
+
+	if err := p.producer.Publish([]byte(cacheKey), value); err != nil {
+		log.Printf("Kafka publish failed (key=%s): %v", cacheKey, err)
+	} else {
+		log.Printf("Refresh requested: %s", cacheKey)
 	}
 }
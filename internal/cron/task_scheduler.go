@@ -0,0 +1,127 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"service-info/internal/commands"
+	messaging "service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/quota"
+	"service-info/internal/repositories"
+	"service-info/internal/schedule"
+)
+
+// TaskScheduler публикует задачи с наступившим сроком запуска как команды
+// в топик популярных запросов и записывает результат каждого запуска.
+type TaskScheduler struct {
+	adminRepo *repositories.AdminRepository
+	commands  *commands.Registry
+	producer  messaging.SyncProducer
+	quota     *quota.Tracker
+	interval  time.Duration
+	batchSize int
+}
+
+func NewTaskScheduler(
+	adminRepo *repositories.AdminRepository,
+	registry *commands.Registry,
+	producer messaging.SyncProducer,
+	quotaTracker *quota.Tracker,
+	interval time.Duration,
+) *TaskScheduler {
+	return &TaskScheduler{
+		adminRepo: adminRepo,
+		commands:  registry,
+		producer:  producer,
+		quota:     quotaTracker,
+		interval:  interval,
+		batchSize: 100,
+	}
+}
+
+func (s *TaskScheduler) Start(ctx context.Context) {
+	log.Printf("🕗 TaskScheduler started (interval: %v)", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.RunOnce(ctx); err != nil {
+				log.Printf("TaskScheduler iteration failed: %v", err)
+			}
+
+		case <-ctx.Done():
+			log.Println("TaskScheduler stopped")
+			return
+		}
+	}
+}
+
+func (s *TaskScheduler) RunOnce(ctx context.Context) error {
+	if !stillLeader(ctx) {
+		return errNotLeader
+	}
+
+	now := time.Now()
+	tasks, err := s.adminRepo.ClaimDue(ctx, leaderTerm(ctx), now, s.batchSize, func(task models.Task) (*time.Time, error) {
+		next, err := schedule.Next(task.Schedule, now)
+		if err != nil {
+			// Битое расписание: задача выключается и не публикуется
+			log.Printf("Task %d has invalid schedule %q: %v", task.ID, task.Schedule, err)
+			return nil, err
+		}
+		return &next, nil
+	})
+	if errors.Is(err, repositories.ErrStaleTerm) {
+		return errNotLeader
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		status, runErr := s.run(ctx, task)
+		if err := s.adminRepo.RecordRun(ctx, leaderTerm(ctx), task.ID, now, status, runErr); err != nil {
+			log.Printf("Failed to record run of task %d: %v", task.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *TaskScheduler) run(ctx context.Context, task models.Task) (string, error) {
+	// Ключ сообщения — ключ кэша команды: так воркер и префетч популярных
+	// запросов схлопывают обновления одного и того же ключа
+	cmd, ok := s.commands.ByType(task.Title)
+	if !ok || cmd.CacheKey == nil {
+		return models.TaskStatusFailed, fmt.Errorf("%w: task type %q", commands.ErrUnknownCommand, task.Title)
+	}
+
+	provider := quota.TaskProviders[task.Title]
+	if !s.quota.Allows(ctx, provider, quota.PriorityBackground) {
+		log.Printf("⏸ Quota low for %s, scheduled task %d skipped", provider, task.ID)
+		return models.TaskStatusSkipped, fmt.Errorf("%s: %w", provider, quota.ErrReserved)
+	}
+
+	req := models.PopularRequest{Type: task.Title, Args: task.Args}
+	value, err := json.Marshal(req)
+	if err != nil {
+		return models.TaskStatusFailed, err
+	}
+
+	key := []byte(cmd.CacheKey(task.Args))
+	if err := s.producer.Publish(key, value); err != nil {
+		log.Printf("Kafka publish failed for task %d (key=%s): %v", task.ID, string(key), err)
+		return models.TaskStatusFailed, err
+	}
+
+	log.Printf("Scheduled task %d published: %s", task.ID, string(key))
+	return models.TaskStatusPublished, nil
+}
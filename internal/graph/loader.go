@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrTooComplex = errors.New("query exceeds complexity limit")
+
+type loaderKey struct{}
+
+// loader живёт один запрос: одинаковые ключи кэша внутри запроса читаются
+// один раз, даже если поля резолвятся параллельно, и каждый резолвер
+// с обращением к данным списывает единицу из бюджета сложности.
+type loader struct {
+	mu      sync.Mutex
+	calls   map[string]*call
+	cost    int
+	maxCost int
+}
+
+type call struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+func withLoader(ctx context.Context, maxCost int) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &loader{calls: make(map[string]*call), maxCost: maxCost})
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	if l, ok := ctx.Value(loaderKey{}).(*loader); ok {
+		return l
+	}
+	// Вне Exec (например, в тестах резолверов) — без дедупликации и лимита
+	return &loader{calls: make(map[string]*call)}
+}
+
+// charge списывает стоимость резолвера из бюджета запроса
+func (l *loader) charge() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cost++
+	if l.maxCost > 0 && l.cost > l.maxCost {
+		return ErrTooComplex
+	}
+	return nil
+}
+
+// load возвращает результат fetch по ключу, вызывая его не больше раза за запрос
+func load[T any](ctx context.Context, key string, fetch func() (*T, error)) (*T, error) {
+	l := loaderFrom(ctx)
+	if err := l.charge(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	c, ok := l.calls[key]
+	if !ok {
+		c = &call{done: make(chan struct{})}
+		l.calls[key] = c
+	}
+	l.mu.Unlock()
+
+	if ok {
+		<-c.done
+	} else {
+		c.value, c.err = fetch()
+		close(c.done)
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.value.(*T), nil
+}
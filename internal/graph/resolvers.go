@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/services"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type queryResolver struct {
+	weather  *services.CacheService[models.Weather]
+	exchange *services.CacheService[models.ExchangeRate]
+	prefs    *services.PreferencesService
+	popular  *services.PopularService
+	recorder *services.RequestRecorder
+}
+
+func userID(ctx context.Context) int64 {
+	id, _ := ctx.Value(middleware.UserIDKey).(int64)
+	return id
+}
+
+// loadWeather — общий путь для Query.weather и City.weather
+func (q *queryResolver) loadWeather(ctx context.Context, city string) (*weatherResolver, error) {
+	city = strings.TrimSpace(city)
+	if city == "" {
+		return nil, errors.New("city must not be empty")
+	}
+
+	weather, err := load(ctx, services.WeatherFetcher{}.CacheKey(city), func() (*models.Weather, error) {
+		w, err := q.weather.Get(ctx, city)
+		if err != nil {
+			log.Printf("GraphQL weather %s: %v", city, err)
+			return nil, errors.New("weather unavailable")
+		}
+		q.recorder.Record("weather", userID(ctx), city)
+		return w, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &weatherResolver{w: weather}, nil
+}
+
+// loadRate — общий путь для Query.exchange и CurrencyPair.rate
+func (q *queryResolver) loadRate(ctx context.Context, base, target string) (*rateResolver, error) {
+	base, target = strings.TrimSpace(base), strings.TrimSpace(target)
+	if base == "" || target == "" {
+		return nil, errors.New("base and target must not be empty")
+	}
+
+	rate, err := load(ctx, services.ExchangeFetcher{}.CacheKey(base, target), func() (*models.ExchangeRate, error) {
+		r, err := q.exchange.Get(ctx, base, target)
+		if err != nil {
+			log.Printf("GraphQL exchange %s -> %s: %v", base, target, err)
+			return nil, errors.New("exchange rate unavailable")
+		}
+		q.recorder.Record("exchange", userID(ctx), base, target)
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rateResolver{r: rate}, nil
+}
+
+func (q *queryResolver) Weather(ctx context.Context, args struct{ City string }) (*weatherResolver, error) {
+	return q.loadWeather(ctx, args.City)
+}
+
+func (q *queryResolver) Exchange(ctx context.Context, args struct{ Base, Target string }) (*rateResolver, error) {
+	return q.loadRate(ctx, args.Base, args.Target)
+}
+
+func (q *queryResolver) Me(ctx context.Context) (*meResolver, error) {
+	id := userID(ctx)
+	prefs, err := load(ctx, "prefs:"+strconv.FormatInt(id, 10), func() (*models.UserPreferences, error) {
+		return q.prefs.Get(ctx, id)
+	})
+	if err != nil {
+		log.Printf("GraphQL preferences of %d: %v", id, err)
+		return nil, errors.New("preferences unavailable")
+	}
+	return &meResolver{q: q, prefs: prefs}, nil
+}
+
+func (q *queryResolver) Popular(ctx context.Context, args struct{ Type, Window string }) (*popularResolver, error) {
+	if err := loaderFrom(ctx).charge(); err != nil {
+		return nil, err
+	}
+
+	taskType := strings.ToLower(strings.TrimSpace(args.Type))
+	window := strings.TrimSpace(args.Window)
+	if window == "" {
+		window = services.DefaultPopularWindow
+	}
+
+	snapshot, items, err := q.popular.Get(ctx, taskType, window)
+	if errors.Is(err, services.ErrUnknownWindow) {
+		return nil, errors.New("window must be one of: 1h, 24h, 7d")
+	}
+	if errors.Is(err, services.ErrUnknownType) {
+		return nil, errors.New("type must be one of: " + strings.Join(q.popular.Types(), ", "))
+	}
+	if err != nil {
+		log.Printf("GraphQL popular (%s, %s): %v", taskType, window, err)
+		return nil, errors.New("popular requests unavailable")
+	}
+	return &popularResolver{window: window, snapshot: snapshot, items: items}, nil
+}
+
+type weatherResolver struct{ w *models.Weather }
+
+func (r *weatherResolver) City() string          { return r.w.City }
+func (r *weatherResolver) TempCelsius() float64  { return r.w.Temp }
+func (r *weatherResolver) FeelsLike() float64    { return r.w.FeelsLike }
+func (r *weatherResolver) Humidity() int32       { return int32(r.w.Humidity) }
+func (r *weatherResolver) Condition() string     { return r.w.Condition }
+func (r *weatherResolver) WindKph() float64      { return r.w.WindKPH }
+func (r *weatherResolver) PressureMb() float64   { return r.w.PressureMB }
+func (r *weatherResolver) CloudPercent() int32   { return int32(r.w.Cloud) }
+func (r *weatherResolver) VisibilityKm() float64 { return r.w.VisibilityKM }
+func (r *weatherResolver) UpdatedAt() string     { return r.w.Updated.Format(time.RFC3339) }
+
+type rateResolver struct{ r *models.ExchangeRate }
+
+func (r *rateResolver) Base() string      { return r.r.Base }
+func (r *rateResolver) Target() string    { return r.r.Target }
+func (r *rateResolver) Rate() float64     { return r.r.Rate }
+func (r *rateResolver) UpdatedAt() string { return r.r.Updated }
+
+type meResolver struct {
+	q     *queryResolver
+	prefs *models.UserPreferences
+}
+
+func (r *meResolver) ID() graphql.ID {
+	return graphql.ID(strconv.FormatInt(r.prefs.UserID, 10))
+}
+
+func (r *meResolver) DefaultCity() *string {
+	if r.prefs.DefaultCity == "" {
+		return nil
+	}
+	return &r.prefs.DefaultCity
+}
+
+func (r *meResolver) FavoriteCities() []*cityResolver {
+	out := make([]*cityResolver, 0, len(r.prefs.FavoriteCities))
+	for _, city := range r.prefs.FavoriteCities {
+		out = append(out, &cityResolver{q: r.q, name: city})
+	}
+	return out
+}
+
+func (r *meResolver) FavoritePairs() []*pairResolver {
+	out := make([]*pairResolver, 0, len(r.prefs.FavoritePairs))
+	for _, pair := range r.prefs.FavoritePairs {
+		out = append(out, &pairResolver{q: r.q, pair: pair})
+	}
+	return out
+}
+
+type cityResolver struct {
+	q    *queryResolver
+	name string
+}
+
+func (r *cityResolver) Name() string { return r.name }
+
+func (r *cityResolver) Weather(ctx context.Context) (*weatherResolver, error) {
+	return r.q.loadWeather(ctx, r.name)
+}
+
+type pairResolver struct {
+	q    *queryResolver
+	pair models.CurrencyPair
+}
+
+func (r *pairResolver) Base() string   { return r.pair.Base }
+func (r *pairResolver) Target() string { return r.pair.Target }
+
+func (r *pairResolver) Rate(ctx context.Context) (*rateResolver, error) {
+	return r.q.loadRate(ctx, r.pair.Base, r.pair.Target)
+}
+
+type popularResolver struct {
+	window   string
+	snapshot *models.PopularSnapshot
+	items    []models.PopularItem
+}
+
+func (r *popularResolver) Window() string { return r.window }
+
+func (r *popularResolver) GeneratedAt() *string {
+	if r.snapshot == nil || r.snapshot.GeneratedAt.IsZero() {
+		return nil
+	}
+	s := r.snapshot.GeneratedAt.Format(time.RFC3339)
+	return &s
+}
+
+func (r *popularResolver) Items() []*popularItemResolver {
+	out := make([]*popularItemResolver, 0, len(r.items))
+	for i := range r.items {
+		out = append(out, &popularItemResolver{item: &r.items[i]})
+	}
+	return out
+}
+
+type popularItemResolver struct{ item *models.PopularItem }
+
+func (r *popularItemResolver) Rank() int32    { return int32(r.item.Rank) }
+func (r *popularItemResolver) Type() string   { return r.item.Type }
+func (r *popularItemResolver) Count() int32   { return int32(r.item.Count) }
+func (r *popularItemResolver) Score() float64 { return r.item.Score }
+
+func (r *popularItemResolver) Args() []*argResolver {
+	out := make([]*argResolver, 0, len(r.item.Args))
+	for name, value := range r.item.Args {
+		out = append(out, &argResolver{name: name, value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
+
+func (r *popularItemResolver) Value() *string {
+	if len(r.item.Value) == 0 {
+		return nil
+	}
+	s := string(r.item.Value)
+	return &s
+}
+
+type argResolver struct{ name, value string }
+
+func (r *argResolver) Name() string  { return r.name }
+func (r *argResolver) Value() string { return r.value }
@@ -0,0 +1,58 @@
+// Package graph — GraphQL-эндпоинт для дашбордов: погода по нескольким
+// городам, курсы, настройки и популярное за один запрос. Данные идут через
+// те же сервисы, что и REST, поэтому кэш и учёт популярности общие.
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"service-info/internal/models"
+	"service-info/internal/services"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Limits ограничивают стоимость одного запроса
+type Limits struct {
+	MaxDepth      int // вложенность полей
+	MaxComplexity int // число резолверов с обращением к данным
+}
+
+type Schema struct {
+	schema        *graphql.Schema
+	maxComplexity int
+}
+
+func NewSchema(
+	weather *services.CacheService[models.Weather],
+	exchange *services.CacheService[models.ExchangeRate],
+	prefs *services.PreferencesService,
+	popular *services.PopularService,
+	recorder *services.RequestRecorder,
+	limits Limits,
+) *Schema {
+	root := &queryResolver{
+		weather:  weather,
+		exchange: exchange,
+		prefs:    prefs,
+		popular:  popular,
+		recorder: recorder,
+	}
+	return &Schema{
+		schema: graphql.MustParseSchema(schemaSDL, root,
+			graphql.MaxDepth(limits.MaxDepth),
+			graphql.MaxQueryLength(8192),
+		),
+		maxComplexity: limits.MaxComplexity,
+	}
+}
+
+// Exec выполняет запрос со своим загрузчиком: дедупликация и бюджет
+// сложности действуют в пределах одного запроса
+func (s *Schema) Exec(ctx context.Context, query, operationName string, variables map[string]any) *graphql.Response {
+	return s.schema.Exec(withLoader(ctx, s.maxComplexity), query, operationName, variables)
+}
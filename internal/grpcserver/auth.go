@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"service-info/internal/middleware"
+	pb "service-info/internal/pb/serviceinfov1"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userIDHeader — аналог заголовка X-User-ID в HTTP API
+const userIDHeader = "x-user-id"
+
+// authenticate читает x-user-id из метаданных и проверяет user:<id> в Redis,
+// как middleware.AuthRequired. CreateUser только читает id: регистрация открыта.
+func authenticate(ctx context.Context, redisClient *redis.Client, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(userIDHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil, status.Error(codes.Unauthenticated, "x-user-id metadata required")
+	}
+
+	userID, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil || userID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid x-user-id")
+	}
+
+	if method != pb.ServiceInfo_CreateUser_FullMethodName {
+		exists, err := redisClient.Exists(ctx, "user:"+values[0]).Result()
+		if err != nil {
+			log.Printf("❌ Redis EXISTS error for user:%s: %v", values[0], err)
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+		if exists == 0 {
+			return nil, status.Error(codes.Unauthenticated, "user not registered, use /auth in Telegram bot")
+		}
+	}
+
+	return context.WithValue(ctx, middleware.UserIDKey, userID), nil
+}
+
+func unaryAuth(redisClient *redis.Client) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, redisClient, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(redisClient *redis.Client) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), redisClient, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authStream подменяет контекст потока на контекст с user_id
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func userIDFromContext(ctx context.Context) int64 {
+	userID, _ := ctx.Value(middleware.UserIDKey).(int64)
+	return userID
+}
@@ -0,0 +1,265 @@
+// Package grpcserver — gRPC API для других Go-сервисов. Работает поверх тех же
+// сервисов, что и HTTP-хэндлеры, поэтому кэш, популярность и квоты общие.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"service-info/internal/api"
+	"service-info/internal/models"
+	pb "service-info/internal/pb/serviceinfov1"
+	"service-info/internal/quota"
+	"service-info/internal/services"
+	"service-info/internal/stream"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxWatchKeys = 50
+
+type Server struct {
+	pb.UnimplementedServiceInfoServer
+
+	weather  *services.CacheService[models.Weather]
+	exchange *services.CacheService[models.ExchangeRate]
+	users    *services.UserService
+	popular  *services.PopularService
+	prefs    *services.PreferencesService
+	recorder *services.RequestRecorder
+	hub      *stream.Hub
+}
+
+func NewServer(
+	weather *services.CacheService[models.Weather],
+	exchange *services.CacheService[models.ExchangeRate],
+	users *services.UserService,
+	popular *services.PopularService,
+	prefs *services.PreferencesService,
+	recorder *services.RequestRecorder,
+	hub *stream.Hub,
+) *Server {
+	return &Server{
+		weather:  weather,
+		exchange: exchange,
+		users:    users,
+		popular:  popular,
+		prefs:    prefs,
+		recorder: recorder,
+		hub:      hub,
+	}
+}
+
+// Register создаёт grpc.Server с авторизацией по x-user-id и регистрирует сервис
+func (s *Server) Register(redisClient *redis.Client) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryAuth(redisClient)),
+		grpc.StreamInterceptor(streamAuth(redisClient)),
+	)
+	pb.RegisterServiceInfoServer(srv, s)
+	return srv
+}
+
+func (s *Server) GetWeather(ctx context.Context, req *pb.GetWeatherRequest) (*pb.Weather, error) {
+	userID := userIDFromContext(ctx)
+
+	city := strings.TrimSpace(req.GetCity())
+	if city == "" {
+		city = s.prefs.DefaultCity(ctx, userID)
+	}
+	if city == "" {
+		return nil, status.Error(codes.InvalidArgument, "city is required when no default city is set in preferences")
+	}
+
+	weather, err := s.weather.Get(ctx, city)
+	if err != nil {
+		log.Printf("gRPC GetWeather %s: %v", city, err)
+		return nil, fetchError(err, "city not found", "weather unavailable")
+	}
+	s.recorder.Record("weather", userID, city)
+
+	return &pb.Weather{
+		City:         weather.City,
+		TempCelsius:  weather.Temp,
+		FeelsLike:    weather.FeelsLike,
+		Humidity:     int32(weather.Humidity),
+		Condition:    weather.Condition,
+		WindKph:      weather.WindKPH,
+		PressureMb:   weather.PressureMB,
+		CloudPercent: int32(weather.Cloud),
+		VisibilityKm: weather.VisibilityKM,
+		UpdatedAt:    formatTime(weather.Updated),
+	}, nil
+}
+
+func (s *Server) GetExchangeRate(ctx context.Context, req *pb.GetExchangeRateRequest) (*pb.ExchangeRate, error) {
+	userID := userIDFromContext(ctx)
+
+	base := strings.TrimSpace(req.GetBase())
+	target := strings.TrimSpace(req.GetTarget())
+	if base == "" && target == "" {
+		if pair, ok := s.prefs.DefaultPair(ctx, userID); ok {
+			base, target = pair.Base, pair.Target
+		}
+	}
+	if base == "" || target == "" {
+		return nil, status.Error(codes.InvalidArgument, "base and target are required when no favorite pairs are set in preferences")
+	}
+
+	rate, err := s.exchange.Get(ctx, base, target)
+	if err != nil {
+		log.Printf("gRPC GetExchangeRate %s -> %s: %v", base, target, err)
+		return nil, fetchError(err, "currency not found", "exchange rate unavailable")
+	}
+	s.recorder.Record("exchange", userID, base, target)
+
+	return &pb.ExchangeRate{
+		Base:      rate.Base,
+		Target:    rate.Target,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.Updated,
+	}, nil
+}
+
+// CreateUser — user_id берётся из x-user-id, поле user.user_id игнорируется
+func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	user := models.UserData{
+		UserID:    userIDFromContext(ctx),
+		UserName:  req.GetUser().GetUserName(),
+		FirstName: req.GetUser().GetFirstName(),
+		LastName:  req.GetUser().GetLastName(),
+	}
+
+	if err := s.users.CreateUser(user); err != nil {
+		log.Printf("gRPC CreateUser %d: %v", user.UserID, err)
+		return nil, status.Error(codes.Internal, "failed to create user")
+	}
+	return &pb.CreateUserResponse{}, nil
+}
+
+func (s *Server) GetPopular(ctx context.Context, req *pb.GetPopularRequest) (*pb.GetPopularResponse, error) {
+	taskType := strings.ToLower(strings.TrimSpace(req.GetType()))
+	window := strings.TrimSpace(req.GetWindow())
+	if window == "" {
+		window = services.DefaultPopularWindow
+	}
+
+	snapshot, items, err := s.popular.Get(ctx, taskType, window)
+	if errors.Is(err, services.ErrUnknownWindow) {
+		return nil, status.Error(codes.InvalidArgument, "window must be one of: 1h, 24h, 7d")
+	}
+	if errors.Is(err, services.ErrUnknownType) {
+		return nil, status.Error(codes.InvalidArgument, "type must be one of: "+strings.Join(s.popular.Types(), ", "))
+	}
+	if err != nil {
+		log.Printf("gRPC GetPopular (%s, %s): %v", taskType, window, err)
+		return nil, status.Error(codes.Internal, "popular requests unavailable")
+	}
+
+	resp := &pb.GetPopularResponse{
+		Window:      window,
+		GeneratedAt: formatTime(snapshot.GeneratedAt),
+		Items:       make([]*pb.PopularItem, 0, len(items)),
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, &pb.PopularItem{
+			Rank:  int32(item.Rank),
+			Type:  item.Type,
+			Args:  item.Args,
+			Count: int64(item.Count),
+			Score: item.Score,
+			Value: item.Value,
+		})
+	}
+	return resp, nil
+}
+
+// WatchUpdates — аналог GET /stream: сначала пропущенные после last_event_id
+// обновления из журнала, затем новые по мере записи воркерами.
+func (s *Server) WatchUpdates(req *pb.WatchUpdatesRequest, srv grpc.ServerStreamingServer[pb.CacheUpdate]) error {
+	keys, err := services.CacheKeys(req.GetWeather(), req.GetExchange())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if len(keys) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one of weather or exchange is required")
+	}
+	if len(keys) > maxWatchKeys {
+		return status.Errorf(codes.InvalidArgument, "at most %d keys per stream", maxWatchKeys)
+	}
+
+	ctx := srv.Context()
+
+	// Подписываемся до чтения журнала, чтобы не потерять события между ними
+	sub := s.hub.Subscribe(keys...)
+	defer sub.Close()
+
+	lastID := req.GetLastEventId()
+	if lastID != "" {
+		missed, err := s.hub.Replay(ctx, lastID, keys)
+		if err != nil {
+			log.Printf("gRPC WatchUpdates replay after %s failed: %v", lastID, err)
+		}
+		for _, u := range missed {
+			if err := srv.Send(toCacheUpdate(u)); err != nil {
+				return err
+			}
+			lastID = u.ID
+		}
+	}
+
+	for {
+		select {
+		case u, ok := <-sub.C():
+			if !ok {
+				// Хаб отключил медленного клиента — он переподключится с last_event_id
+				return status.Error(codes.ResourceExhausted, "client too slow, reconnect with last_event_id")
+			}
+			if lastID != "" && !stream.After(u.ID, lastID) {
+				continue
+			}
+			if err := srv.Send(toCacheUpdate(u)); err != nil {
+				return err
+			}
+			lastID = u.ID
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func toCacheUpdate(u stream.Update) *pb.CacheUpdate {
+	return &pb.CacheUpdate{Id: u.ID, Key: u.Key, Data: u.Data}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// fetchError переводит вид ошибки провайдера в gRPC-код — так же, как writeFetchError для HTTP
+func fetchError(err error, notFound, unavailable string) error {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, api.ErrNotFound):
+		return status.Error(codes.NotFound, notFound)
+	case errors.Is(err, api.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, api.ErrQuotaExceeded), errors.Is(err, quota.ErrExhausted), errors.Is(err, quota.ErrReserved):
+		return status.Error(codes.ResourceExhausted, unavailable)
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, api.ErrUpstreamUnavailable):
+		return status.Error(codes.Unavailable, unavailable)
+	default:
+		return status.Error(codes.Internal, unavailable)
+	}
+}
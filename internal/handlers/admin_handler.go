@@ -2,102 +2,110 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
-	"time"
 
-	"service-info/internal/models"
+	"service-info/internal/apierror"
+	"service-info/internal/commands"
+	"service-info/internal/schedule"
 	"service-info/internal/services"
 )
 
 type AdminHandler struct {
-	service *services.AdminService
+	service  *services.AdminService
+	commands *commands.Registry
 }
 
-func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
-	return &AdminHandler{service: adminService}
+func NewAdminHandler(adminService *services.AdminService, registry *commands.Registry) *AdminHandler {
+	return &AdminHandler{service: adminService, commands: registry}
 }
 
 func (h *AdminHandler) CreatePopular(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Text string `json:"text"`
+		Text     string `json:"text"`
+		Schedule string `json:"schedule"`
+		Enabled  *bool  `json:"enabled"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
 		return
 	}
 
 	text := strings.TrimSpace(req.Text)
 	if text == "" {
-		http.Error(w, "field 'text' is required", http.StatusBadRequest)
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "field 'text' is required")
 		return
 	}
 
-	task, err := parseCommandToTask(text)
+	if fields := strings.Fields(text); fields[0] == "/help" {
+		h.writeHelp(w, r, strings.Join(fields[1:], " "))
+		return
+	}
+
+	task, err := h.commands.ParseTask(text)
 	if err != nil {
-		http.Error(w, "parse error: "+err.Error(), http.StatusBadRequest)
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "parse error: "+err.Error())
 		return
 	}
 
-	if err := h.service.SaveTask(r.Context(), task); err != nil {
+	task.Schedule = strings.TrimSpace(req.Schedule)
+	task.Enabled = req.Enabled == nil || *req.Enabled
+
+	id, err := h.service.SaveTask(r.Context(), task)
+	if errors.Is(err, schedule.ErrInvalid) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
 		log.Printf("Failed to save task: %v", err)
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	resp := struct {
-		OK   bool              `json:"ok"`
-		Type string            `json:"type"`
-		Args map[string]string `json:"args"`
+		OK       bool              `json:"ok"`
+		ID       int               `json:"id"`
+		Type     string            `json:"type"`
+		Args     map[string]string `json:"args"`
+		Schedule string            `json:"schedule,omitempty"`
 	}{
-		OK:   true,
-		Type: task.Title,
-		Args: task.Args,
+		OK:       true,
+		ID:       id,
+		Type:     task.Title,
+		Args:     task.Args,
+		Schedule: task.Schedule,
 	}
 	json.NewEncoder(w).Encode(resp)
 }
 
-func parseCommandToTask(text string) (models.Task, error) {
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return models.Task{}, fmt.Errorf("empty command")
+// ListCommands — GET /admin/commands
+func (h *AdminHandler) ListCommands(w http.ResponseWriter, r *http.Request) {
+	type commandView struct {
+		commands.Command
+		Usage string `json:"usage"`
+	}
+
+	cmds := h.commands.Commands()
+	views := make([]commandView, 0, len(cmds))
+	for _, cmd := range cmds {
+		views = append(views, commandView{Command: cmd, Usage: cmd.Usage()})
 	}
 
-	cmd := parts[0]
-	args := make(models.TaskArgs)
-
-	switch cmd {
-	case "/weather":
-		if len(parts) < 2 {
-			return models.Task{}, fmt.Errorf("usage: /weather <city>")
-		}
-		args["city"] = strings.ToLower(parts[1])
-		if len(parts) > 2 {
-			args["lang"] = strings.ToLower(parts[2])
-		}
-		return models.Task{
-			Title:     "weather",
-			Args:      args,
-			CreatedAt: time.Now(),
-		}, nil
-
-	case "/exchange":
-		if len(parts) < 3 {
-			return models.Task{}, fmt.Errorf("usage: /exchange <base> <target>")
-		}
-		args["base"] = strings.ToUpper(parts[1])
-		args["target"] = strings.ToUpper(parts[2])
-		return models.Task{
-			Title:     "exchange",
-			Args:      args,
-			CreatedAt: time.Now(),
-		}, nil
-
-	default:
-		return models.Task{}, fmt.Errorf("unknown command: %s", cmd)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"commands": views})
+}
+
+func (h *AdminHandler) writeHelp(w http.ResponseWriter, r *http.Request, topic string) {
+	help, err := h.commands.Help(topic)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"ok": true, "help": help})
 }
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"service-info/internal/apierror"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/schedule"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultTasksLimit = 50
+	maxTasksLimit     = 500
+)
+
+// ListTasks — GET /admin/tasks?title=&arg=city:moscow&since=&until=&limit=&offset=
+func (h *AdminHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := repositories.TaskFilter{
+		Title: strings.TrimSpace(q.Get("title")),
+		Limit: defaultTasksLimit,
+	}
+
+	for _, arg := range q["arg"] {
+		k, v, ok := strings.Cut(arg, ":")
+		if !ok || k == "" {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "arg must look like key:value")
+			return
+		}
+		if filter.Args == nil {
+			filter.Args = make(models.TaskArgs)
+		}
+		filter.Args[k] = v
+	}
+
+	var err error
+	if filter.Since, err = parseTimeParam(q.Get("since")); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "since must be RFC3339")
+		return
+	}
+	if filter.Until, err = parseTimeParam(q.Get("until")); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "until must be RFC3339")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "limit must be a positive number")
+			return
+		}
+		if filter.Limit > maxTasksLimit {
+			filter.Limit = maxTasksLimit
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil || filter.Offset < 0 {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "offset must be a non-negative number")
+			return
+		}
+	}
+
+	tasks, total, err := h.service.ListTasks(r.Context(), filter)
+	if err != nil {
+		log.Printf("Failed to list tasks: %v", err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Items  []models.Task `json:"items"`
+		Total  int           `json:"total"`
+		Limit  int           `json:"limit"`
+		Offset int           `json:"offset"`
+	}{
+		Items:  tasks,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	})
+}
+
+// GetTask — GET /admin/tasks/{id}
+func (h *AdminHandler) GetTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := taskIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := h.service.GetTask(r.Context(), id)
+	if errors.Is(err, repositories.ErrTaskNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get task %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// UpdateTask — PATCH /admin/tasks/{id} {"schedule":"*/10 * * * *","enabled":true}
+func (h *AdminHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := taskIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Schedule *string `json:"schedule"`
+		Enabled  *bool   `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+
+	task, err := h.service.GetTask(r.Context(), id)
+	if errors.Is(err, repositories.ErrTaskNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get task %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	expr, enabled := task.Schedule, task.Enabled
+	if req.Schedule != nil {
+		expr = strings.TrimSpace(*req.Schedule)
+	}
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	err = h.service.UpdateSchedule(r.Context(), id, expr, enabled)
+	if errors.Is(err, schedule.ErrInvalid) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if errors.Is(err, repositories.ErrTaskNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update task %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	h.GetTask(w, r)
+}
+
+// DeleteTask — DELETE /admin/tasks/{id}
+func (h *AdminHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := taskIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteTask(r.Context(), id)
+	if errors.Is(err, repositories.ErrTaskNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "task not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete task %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteTasks — DELETE /admin/tasks?title=weather&older_than=720h
+func (h *AdminHandler) DeleteTasks(w http.ResponseWriter, r *http.Request) {
+	title := strings.TrimSpace(r.URL.Query().Get("title"))
+
+	var olderThan time.Duration
+	if v := r.URL.Query().Get("older_than"); v != "" {
+		var err error
+		if olderThan, err = time.ParseDuration(v); err != nil || olderThan <= 0 {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "older_than must be a positive duration, e.g. 720h")
+			return
+		}
+	}
+
+	if title == "" && olderThan == 0 {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "title or older_than is required")
+		return
+	}
+
+	deleted, err := h.service.DeleteTasks(r.Context(), title, olderThan)
+	if err != nil {
+		log.Printf("Failed to bulk delete tasks: %v", err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"deleted": deleted})
+}
+
+func taskIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid task id")
+		return 0, false
+	}
+	return id, true
+}
+
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
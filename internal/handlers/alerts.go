@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"service-info/internal/apierror"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultEventsLimit = 50
+	maxEventsLimit     = 200
+)
+
+type AlertHandler struct {
+	service *services.AlertService
+}
+
+func NewAlertHandler(service *services.AlertService) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+// ListExchangeAlerts — GET /me/alerts/exchange
+func (h *AlertHandler) ListExchangeAlerts(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	alerts, err := h.service.ListExchangeAlerts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to list exchange alerts of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"items": alerts})
+}
+
+// CreateExchangeAlert — POST /me/alerts/exchange
+// {"base":"USD","target":"RUB","direction":"above","threshold":100,"cooldown_seconds":3600}
+func (h *AlertHandler) CreateExchangeAlert(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	var alert models.ExchangeAlert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+	alert.UserID = userID
+
+	created, err := h.service.CreateExchangeAlert(r.Context(), alert)
+	if errors.Is(err, services.ErrInvalidAlert) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create exchange alert for %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// DeleteExchangeAlert — DELETE /me/alerts/exchange/{id}
+func (h *AlertHandler) DeleteExchangeAlert(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "id must be a number")
+		return
+	}
+
+	err = h.service.DeleteExchangeAlert(r.Context(), userID, id)
+	if errors.Is(err, repositories.ErrAlertNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "alert not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete exchange alert %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWeatherAlerts — GET /me/alerts/weather
+func (h *AlertHandler) ListWeatherAlerts(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	alerts, err := h.service.ListWeatherAlerts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to list weather alerts of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"items": alerts})
+}
+
+// CreateWeatherAlert — POST /me/alerts/weather
+// {"city":"Moscow","metric":"temp","operator":"below","threshold":-20}
+// {"city":"Moscow","metric":"condition","operator":"contains","match":"snow"}
+func (h *AlertHandler) CreateWeatherAlert(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	var alert models.WeatherAlert
+	if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+	alert.UserID = userID
+
+	created, err := h.service.CreateWeatherAlert(r.Context(), alert)
+	if errors.Is(err, services.ErrInvalidAlert) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create weather alert for %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// DeleteWeatherAlert — DELETE /me/alerts/weather/{id}
+func (h *AlertHandler) DeleteWeatherAlert(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "id must be a number")
+		return
+	}
+
+	err = h.service.DeleteWeatherAlert(r.Context(), userID, id)
+	if errors.Is(err, repositories.ErrAlertNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "alert not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete weather alert %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListEvents — GET /me/alerts/events?limit=50, сработавшие правила, новые первыми
+func (h *AlertHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	limit := defaultEventsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "limit must be a positive number")
+			return
+		}
+		limit = min(n, maxEventsLimit)
+	}
+
+	events, err := h.service.ListEvents(r.Context(), userID, limit)
+	if err != nil {
+		log.Printf("Failed to list alert events of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"items": events})
+}
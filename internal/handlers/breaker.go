@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"service-info/internal/breaker"
+)
+
+type BreakerHandler struct {
+	breakers *breaker.Set
+}
+
+func NewBreakerHandler(breakers *breaker.Set) *BreakerHandler {
+	return &BreakerHandler{breakers: breakers}
+}
+
+// GetBreakers возвращает состояние выключателя по каждому провайдеру
+func (h *BreakerHandler) GetBreakers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"providers": h.breakers.Providers()})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"service-info/internal/apierror"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+)
+
+type DigestHandler struct {
+	service *services.DigestService
+}
+
+func NewDigestHandler(service *services.DigestService) *DigestHandler {
+	return &DigestHandler{service: service}
+}
+
+// GetDigest — GET /me/digest
+func (h *DigestHandler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	digest, err := h.service.Get(r.Context(), userID)
+	if errors.Is(err, repositories.ErrDigestNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "digest is not scheduled")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get digest of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(digest)
+}
+
+// PutDigest — PUT /me/digest {"time_of_day":"08:00","timezone":"Europe/Moscow","enabled":true}
+func (h *DigestHandler) PutDigest(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	digest := models.DigestSchedule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&digest); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+	digest.UserID = userID
+
+	saved, err := h.service.Save(r.Context(), digest)
+	if errors.Is(err, services.ErrInvalidDigest) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to save digest of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// DeleteDigest — DELETE /me/digest
+func (h *DigestHandler) DeleteDigest(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	err := h.service.Delete(r.Context(), userID)
+	if errors.Is(err, repositories.ErrDigestNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "digest is not scheduled")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete digest of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
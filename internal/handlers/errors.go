@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"service-info/internal/api"
+	"service-info/internal/apierror"
+	"service-info/internal/quota"
+)
+
+// writeFetchError отвечает на ошибку получения данных у провайдера по её виду:
+// неизвестный город или валюта — 404, неверные параметры — 400, отказ
+// провайдера в ключе — 502, недоступность, истёкший дедлайн или исчерпанная
+// квота — 503
+func writeFetchError(w http.ResponseWriter, r *http.Request, err error, notFound, unavailable string, details map[string]any) {
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		apierror.WriteDetails(w, r, http.StatusNotFound, apierror.CodeNotFound, notFound, details)
+	case errors.Is(err, api.ErrInvalidInput):
+		apierror.WriteDetails(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error(), details)
+	case errors.Is(err, api.ErrQuotaExceeded), errors.Is(err, quota.ErrExhausted), errors.Is(err, quota.ErrReserved):
+		apierror.WriteDetails(w, r, http.StatusServiceUnavailable, apierror.CodeUpstreamQuotaExceeded, unavailable, details)
+	case errors.Is(err, api.ErrUnauthorized):
+		apierror.WriteDetails(w, r, http.StatusBadGateway, apierror.CodeUpstreamUnavailable, unavailable, details)
+	case errors.Is(err, api.ErrUpstreamUnavailable), errors.Is(err, context.DeadlineExceeded):
+		apierror.WriteDetails(w, r, http.StatusServiceUnavailable, apierror.CodeUpstreamUnavailable, unavailable, details)
+	default:
+		apierror.WriteDetails(w, r, http.StatusInternalServerError, apierror.CodeInternal, unavailable, details)
+	}
+}
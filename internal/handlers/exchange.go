@@ -6,38 +6,52 @@ import (
 	"net/http"
 	"strings"
 
+	"service-info/internal/apierror"
+	"service-info/internal/middleware"
 	"service-info/internal/models"
 	"service-info/internal/services"
 )
 
 type ExchangeHandler struct {
-	service *services.CacheService[models.ExchangeRate]
+	service  *services.CacheService[models.ExchangeRate]
+	recorder *services.RequestRecorder
+	prefs    *services.PreferencesService
 }
 
-func NewExchangeHandler(service *services.CacheService[models.ExchangeRate]) *ExchangeHandler {
-	return &ExchangeHandler{service: service}
+func NewExchangeHandler(
+	service *services.CacheService[models.ExchangeRate],
+	recorder *services.RequestRecorder,
+	prefs *services.PreferencesService,
+) *ExchangeHandler {
+	return &ExchangeHandler{service: service, recorder: recorder, prefs: prefs}
 }
 
 func (h *ExchangeHandler) GetRate(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
 	base := strings.TrimSpace(r.URL.Query().Get("base"))
 	target := strings.TrimSpace(r.URL.Query().Get("target"))
 
+	if base == "" && target == "" {
+		if pair, ok := h.prefs.DefaultPair(r.Context(), userID); ok {
+			base, target = pair.Base, pair.Target
+		}
+	}
+
 	if base == "" || target == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Параметры 'base' и 'target' обязательны"})
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "query parameters 'base' and 'target' are required when no favorite pairs are set in preferences")
 		return
 	}
 
-	rate, err := h.service.Get(base, target)
+	rate, err := h.service.Get(r.Context(), base, target)
 	if err != nil {
 		log.Printf("Ошибка для %s -> %s: %v", base, target, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Не удалось получить курс валют"})
+		writeFetchError(w, r, err, "currency not found", "exchange rate unavailable", map[string]any{"base": base, "target": target})
 		return
 	}
 
+	h.recorder.Record("exchange", userID, base, target)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rate)
 }
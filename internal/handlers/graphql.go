@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"service-info/internal/apierror"
+	"service-info/internal/graph"
+)
+
+type GraphQLHandler struct {
+	schema *graph.Schema
+}
+
+func NewGraphQLHandler(schema *graph.Schema) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema}
+}
+
+// Query — POST /graphql {"query": "...", "operationName": "...", "variables": {...}}
+// Ошибки отдельных полей возвращаются в errors вместе с остальными данными.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Query == "" {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "request body must contain 'query'")
+		return
+	}
+
+	resp := h.schema.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"service-info/internal/apierror"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/services"
+)
+
+type MeHandler struct {
+	prefs *services.PreferencesService
+}
+
+func NewMeHandler(prefs *services.PreferencesService) *MeHandler {
+	return &MeHandler{prefs: prefs}
+}
+
+// GetPreferences — GET /me/preferences
+func (h *MeHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	prefs, err := h.prefs.Get(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to get preferences of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// PutPreferences — PUT /me/preferences, полностью заменяет настройки
+func (h *MeHandler) PutPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	var prefs models.UserPreferences
+	if err := json.NewDecoder(r.Body).Decode(&prefs); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+	prefs.UserID = userID
+
+	saved, err := h.prefs.Save(r.Context(), prefs)
+	if errors.Is(err, services.ErrInvalidPreferences) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to save preferences of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// GetDashboard — GET /me/dashboard, все избранные города и пары одним ответом
+func (h *MeHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	dashboard, err := h.prefs.Dashboard(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to build dashboard of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dashboard)
+}
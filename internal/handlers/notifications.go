@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"service-info/internal/apierror"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type NotificationHandler struct {
+	service *services.NotificationService
+}
+
+func NewNotificationHandler(service *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// ListChannels — GET /me/channels
+func (h *NotificationHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	channels, err := h.service.ListChannels(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to list channels of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"items":     channels,
+		"available": h.service.AvailableChannels(),
+	})
+}
+
+// AddChannel — POST /me/channels {"channel":"telegram","destination":"123456789"}
+// Для webhook ответ содержит secret для проверки подписи — он показывается только здесь.
+func (h *NotificationHandler) AddChannel(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	var ch models.NotificationChannel
+	if err := json.NewDecoder(r.Body).Decode(&ch); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+	ch.UserID = userID
+
+	created, err := h.service.AddChannel(r.Context(), ch)
+	switch {
+	case errors.Is(err, services.ErrInvalidChannel):
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	case errors.Is(err, repositories.ErrChannelExists):
+		apierror.Write(w, r, http.StatusConflict, apierror.CodeConflict, err.Error())
+		return
+	case err != nil:
+		log.Printf("Failed to add channel for %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// DeleteChannel — DELETE /me/channels/{id}
+func (h *NotificationHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "id must be a number")
+		return
+	}
+
+	err = h.service.DeleteChannel(r.Context(), userID, id)
+	if errors.Is(err, repositories.ErrChannelNotFound) {
+		apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, "channel not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete channel %d: %v", id, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries — GET /me/notifications?limit=50, журнал доставки уведомлений
+func (h *NotificationHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	limit := defaultEventsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "limit must be a positive number")
+			return
+		}
+		limit = min(n, maxEventsLimit)
+	}
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), userID, limit)
+	if err != nil {
+		log.Printf("Failed to list deliveries of %d: %v", userID, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"items": deliveries})
+}
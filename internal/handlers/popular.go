@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"service-info/internal/apierror"
+	"service-info/internal/models"
+	"service-info/internal/services"
+)
+
+type PopularHandler struct {
+	service *services.PopularService
+}
+
+func NewPopularHandler(service *services.PopularService) *PopularHandler {
+	return &PopularHandler{service: service}
+}
+
+// GetPopular — GET /popular?type=weather&window=24h
+func (h *PopularHandler) GetPopular(w http.ResponseWriter, r *http.Request) {
+	taskType := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("type")))
+	window := strings.TrimSpace(r.URL.Query().Get("window"))
+	if window == "" {
+		window = services.DefaultPopularWindow
+	}
+
+	snapshot, items, err := h.service.Get(r.Context(), taskType, window)
+	if errors.Is(err, services.ErrUnknownWindow) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "query parameter 'window' must be one of: 1h, 24h, 7d")
+		return
+	}
+	if errors.Is(err, services.ErrUnknownType) {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "query parameter 'type' must be one of: "+strings.Join(h.service.Types(), ", "))
+		return
+	}
+	if err != nil {
+		log.Printf("Ошибка чтения популярных запросов (%s, %s): %v", taskType, window, err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "popular requests unavailable")
+		return
+	}
+
+	var generatedAt *time.Time
+	if !snapshot.GeneratedAt.IsZero() {
+		generatedAt = &snapshot.GeneratedAt
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Window      string               `json:"window"`
+		GeneratedAt *time.Time           `json:"generated_at"`
+		Items       []models.PopularItem `json:"items"`
+	}{
+		Window:      window,
+		GeneratedAt: generatedAt,
+		Items:       items,
+	})
+}
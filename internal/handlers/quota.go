@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"service-info/internal/apierror"
+	"service-info/internal/quota"
+)
+
+type QuotaHandler struct {
+	tracker *quota.Tracker
+}
+
+func NewQuotaHandler(tracker *quota.Tracker) *QuotaHandler {
+	return &QuotaHandler{tracker: tracker}
+}
+
+// GetQuota возвращает остаток бюджета вызовов по каждому провайдеру
+func (h *QuotaHandler) GetQuota(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.tracker.Providers(r.Context())
+	if err != nil {
+		log.Printf("Failed to read quota usage: %v", err)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"providers": usage})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"service-info/internal/apierror"
+	"service-info/internal/services"
+	"service-info/internal/stream"
+)
+
+const (
+	maxStreamKeys     = 50
+	heartbeatInterval = 15 * time.Second
+)
+
+type StreamHandler struct {
+	hub *stream.Hub
+}
+
+func NewStreamHandler(hub *stream.Hub) *StreamHandler {
+	return &StreamHandler{hub: hub}
+}
+
+// Stream — GET /stream?weather=moscow&exchange=usd_eur
+// Server-Sent Events: событие на каждое обновление ключа кэша воркером.
+// Параметры можно повторять или перечислять через запятую. После обрыва
+// браузер переподключается с Last-Event-ID и получает пропущенные события.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	keys, err := streamKeys(r)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "streaming unsupported")
+		return
+	}
+
+	// Подписываемся до чтения журнала, чтобы не потерять события между ними
+	sub := h.hub.Subscribe(keys...)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	if lastID != "" {
+		missed, err := h.hub.Replay(r.Context(), lastID, keys)
+		if err != nil {
+			log.Printf("Stream replay after %s failed: %v", lastID, err)
+		}
+		for _, u := range missed {
+			writeEvent(w, u)
+			lastID = u.ID
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case u, ok := <-sub.C():
+			if !ok {
+				// Хаб отключил медленного клиента — он переподключится с Last-Event-ID
+				return
+			}
+			if lastID != "" && !stream.After(u.ID, lastID) {
+				continue
+			}
+			writeEvent(w, u)
+			lastID = u.ID
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, u stream.Update) {
+	kind, _, _ := strings.Cut(u.Key, ":")
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", u.ID, kind, u.Data)
+}
+
+// streamKeys переводит параметры запроса в ключи кэша
+func streamKeys(r *http.Request) ([]string, error) {
+	q := r.URL.Query()
+	keys, err := services.CacheKeys(splitParam(q["weather"]), splitParam(q["exchange"]))
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one of 'weather' or 'exchange' is required")
+	}
+	if len(keys) > maxStreamKeys {
+		return nil, fmt.Errorf("at most %d keys per stream", maxStreamKeys)
+	}
+	return keys, nil
+}
+
+func splitParam(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"service-info/internal/apierror"
 	"service-info/internal/models"
 	"service-info/internal/services"
 	"strconv"
@@ -20,26 +21,26 @@ func NewUserHandler(service *services.UserService) *UserHandler {
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var user models.UserData
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "Invalid request body")
 		return
 	}
 
 	userIDStr := r.Header.Get("X-User-ID")
 	if userIDStr == "" {
-		http.Error(w, "X-User-ID header is required", http.StatusBadRequest)
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "X-User-ID header is required")
 		return
 	}
 
 	userIDInt, err := strconv.Atoi(userIDStr)
 	if err != nil || userIDInt <= 0 {
-		http.Error(w, "Invalid X-User-ID", http.StatusBadRequest)
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "Invalid X-User-ID")
 		return
 	}
 	user.UserID = int64(userIDInt)
 
 	if err := h.service.CreateUser(user); err != nil {
 		log.Printf("CreateUser failed: %v", err)
-		http.Error(w, "Failed to create user", http.StatusInternalServerError)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "Failed to create user")
 		return
 	}
 
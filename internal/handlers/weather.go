@@ -42,40 +42,158 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
+	"service-info/internal/api"
+	"service-info/internal/apierror"
+	"service-info/internal/commands"
+	"service-info/internal/middleware"
 	"service-info/internal/models"
 	"service-info/internal/services"
 )
 
+const (
+	maxBatchCities = 20
+	batchWorkers   = 5
+)
+
 type WeatherHandler struct {
-	service *services.CacheService[models.Weather]
+	service  *services.CacheService[models.Weather]
+	recorder *services.RequestRecorder
+	prefs    *services.PreferencesService
 }
 
-func NewWeatherHandler(service *services.CacheService[models.Weather]) *WeatherHandler {
-	return &WeatherHandler{service: service}
+func NewWeatherHandler(
+	service *services.CacheService[models.Weather],
+	recorder *services.RequestRecorder,
+	prefs *services.PreferencesService,
+) *WeatherHandler {
+	return &WeatherHandler{service: service, recorder: recorder, prefs: prefs}
 }
 
 func (h *WeatherHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
 	city := strings.TrimSpace(r.URL.Query().Get("city"))
 	if city == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Параметр 'city' обязателен"})
+		city = h.prefs.DefaultCity(r.Context(), userID)
+	}
+	if city == "" {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "query parameter 'city' is required when no default city is set in preferences")
+		return
+	}
+	lang, units, err := weatherOptions(r)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 
-	weather, err := h.service.Get(city)
+	weather, err := h.service.Get(r.Context(), city, lang)
 	if err != nil {
 		log.Printf("Ошибка для %s: %v", city, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Не удалось получить погоду"})
+		writeFetchError(w, r, err, "city not found", "weather unavailable", map[string]any{"city": city})
+		return
+	}
+
+	// В популярность попадают только успешные запросы — опечатки не должны прогревать кэш
+	h.recorder.Record("weather", userID, weatherFields(city, lang)...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(services.WeatherInUnits(weather, units))
+}
+
+// GetWeatherBatch — POST /weather/batch {"cities": ["Moscow", "London"]}
+// Отвечает 200 даже при частичных сбоях: у каждого города свои data или error.
+func (h *WeatherHandler) GetWeatherBatch(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserIDFromContext(r)
+
+	var req struct {
+		Cities []string `json:"cities"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid JSON")
+		return
+	}
+	lang, units, err := weatherOptions(r)
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 
+	// Города с одинаковым ключом кэша (Moscow и moscow) запрашиваем один раз
+	var cities []string
+	seen := make(map[string]bool)
+	for _, city := range req.Cities {
+		city = strings.TrimSpace(city)
+		key := services.WeatherFetcher{}.CacheKey(city, lang)
+		if city == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cities = append(cities, city)
+	}
+	if len(cities) == 0 || len(cities) > maxBatchCities {
+		apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, fmt.Sprintf("field 'cities' must contain 1 to %d cities", maxBatchCities))
+		return
+	}
+
+	params := make([][]string, len(cities))
+	for i, city := range cities {
+		params[i] = []string{city, lang}
+	}
+	results, errs := h.service.GetMany(r.Context(), params, batchWorkers)
+
+	items := make(map[string]any, len(cities))
+	for i, city := range cities {
+		if errs[i] != nil {
+			log.Printf("Ошибка для %s: %v", city, errs[i])
+			message := "weather unavailable"
+			switch {
+			case errors.Is(errs[i], api.ErrNotFound):
+				message = "city not found"
+			case errors.Is(errs[i], api.ErrInvalidInput):
+				message = "invalid city"
+			case errors.Is(errs[i], api.ErrQuotaExceeded):
+				message = "upstream quota exceeded"
+			}
+			items[city] = models.DashboardItem[models.Weather]{Error: message}
+			continue
+		}
+		h.recorder.Record("weather", userID, weatherFields(city, lang)...)
+		if units == services.UnitsImperial {
+			items[city] = models.DashboardItem[models.WeatherImperial]{Data: services.ToImperial(results[i])}
+		} else {
+			items[city] = models.DashboardItem[models.Weather]{Data: results[i]}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(weather)
+	json.NewEncoder(w).Encode(map[string]any{"results": items})
+}
+
+// weatherOptions читает необязательные параметры lang и units
+func weatherOptions(r *http.Request) (lang, units string, err error) {
+	lang = strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lang")))
+	if lang != "" {
+		if err := commands.Letters(2)(lang); err != nil {
+			return "", "", fmt.Errorf("query parameter 'lang' %v", err)
+		}
+	}
+	units, err = services.ParseUnits(r.URL.Query().Get("units"))
+	if err != nil {
+		return "", "", fmt.Errorf("query parameter 'units': %v", err)
+	}
+	return lang, units, nil
+}
+
+// weatherFields — аргументы команды /weather для учёта популярности
+func weatherFields(city, lang string) []string {
+	if lang == "" {
+		return []string{city}
+	}
+	return []string{city, lang}
 }
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"service-info/internal/services"
+	"service-info/internal/stream"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	maxWSKeys       = 100
+	wsWriteWait     = 10 * time.Second
+	wsPongWait      = 60 * time.Second
+	wsPingPeriod    = wsPongWait * 9 / 10
+	wsMaxMessageLen = 4096
+)
+
+// wsRequest — сообщение клиента:
+// {"action":"subscribe","weather":["moscow"],"exchange":["usd_eur"]}
+type wsRequest struct {
+	Action   string   `json:"action"`
+	Weather  []string `json:"weather"`
+	Exchange []string `json:"exchange"`
+}
+
+// wsMessage — сообщение сервера: update, subscribed, error или pong
+type wsMessage struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id,omitempty"`
+	Key   string          `json:"key,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Keys  []string        `json:"keys,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+type WSHandler struct {
+	hub      *stream.Hub
+	upgrader websocket.Upgrader
+}
+
+func NewWSHandler(hub *stream.Hub) *WSHandler {
+	return &WSHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Авторизация по X-User-ID, который браузер не может подставить
+			// в чужой WebSocket, поэтому Origin не проверяем
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Serve — GET /ws. Клиент управляет подпиской сообщениями subscribe/unsubscribe
+// и получает обновления ключей, как только воркеры пишут их в Redis.
+// На соединение — две горутины и ограниченный буфер в хабе: медленного
+// клиента хаб отключает, не задерживая остальных.
+func (h *WSHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту
+		log.Printf("WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	sub := h.hub.Subscribe()
+	defer sub.Close()
+
+	// Ответы на команды пишет только writer, чтобы не писать в conn из двух горутин
+	replies := make(chan wsMessage, 8)
+	done := make(chan struct{})
+	defer close(done)
+	go h.read(conn, sub, replies, done)
+
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+
+	for {
+		var msg wsMessage
+		select {
+		case u, ok := <-sub.C():
+			if !ok {
+				h.close(conn, websocket.ClosePolicyViolation, "client too slow")
+				return
+			}
+			msg = wsMessage{Type: "update", ID: u.ID, Key: u.Key, Data: u.Data}
+
+		case reply, ok := <-replies:
+			if !ok {
+				return
+			}
+			msg = reply
+
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+
+		case <-r.Context().Done():
+			return
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+// read обрабатывает команды клиента; закрывает replies, когда соединение оборвалось.
+// done закрывается, когда writer завершился и ответы больше никто не читает.
+func (h *WSHandler) read(conn *websocket.Conn, sub *stream.Subscription, replies chan<- wsMessage, done <-chan struct{}) {
+	defer close(replies)
+
+	reply := func(msg wsMessage) bool {
+		select {
+		case replies <- msg:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	conn.SetReadLimit(wsMaxMessageLen)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var msg wsMessage
+
+		var req wsRequest
+		err := conn.ReadJSON(&req)
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+			msg = wsMessage{Type: "error", Error: "invalid JSON"}
+		case err != nil:
+			return
+		default:
+			msg = h.apply(sub, req)
+		}
+
+		if !reply(msg) {
+			return
+		}
+	}
+}
+
+func (h *WSHandler) apply(sub *stream.Subscription, req wsRequest) wsMessage {
+	switch req.Action {
+	case "ping":
+		return wsMessage{Type: "pong"}
+	case "subscribe", "unsubscribe":
+	default:
+		return wsMessage{Type: "error", Error: "action must be subscribe, unsubscribe or ping"}
+	}
+
+	keys, err := services.CacheKeys(req.Weather, req.Exchange)
+	if err != nil {
+		return wsMessage{Type: "error", Error: err.Error()}
+	}
+
+	if req.Action == "subscribe" {
+		if len(sub.Keys())+len(keys) > maxWSKeys {
+			return wsMessage{Type: "error", Error: "too many subscriptions"}
+		}
+		sub.Add(keys...)
+	} else {
+		sub.Remove(keys...)
+	}
+	return wsMessage{Type: "subscribed", Keys: sub.Keys()}
+}
+
+func (h *WSHandler) close(conn *websocket.Conn, code int, reason string) {
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteWait))
+}
@@ -5,11 +5,15 @@ type KafkaBundle struct {
 	UserProducer     *Producer
 	ExchangeProducer *Producer
 	PopularProducer  *Producer
+	RequestProducer  *Producer
+	NotifyProducer   *Producer
 
 	WeatherConsumer  *Consumer
 	UserConsumer     *Consumer
 	ExchangeConsumer *Consumer
 	PopularConsumer  *Consumer
+	RequestConsumer  *Consumer
+	NotifyConsumer   *Consumer
 }
 
 func InitKafka() *KafkaBundle {
@@ -18,10 +22,14 @@ func InitKafka() *KafkaBundle {
 		UserProducer:     NewProducer(getEnv("USER_KAFKA_TOPIC", "user-events")),
 		ExchangeProducer: NewProducer(getEnv("EXCHANGE_KAFKA_TOPIC", "exchange-updates")),
 		PopularProducer:  NewProducer("popular-requests"),
+		RequestProducer:  NewProducer(getEnv("REQUEST_EVENTS_KAFKA_TOPIC", "request-events")),
+		NotifyProducer:   NewProducer(getEnv("NOTIFICATIONS_KAFKA_TOPIC", "notifications")),
 
 		WeatherConsumer:  NewConsumer(getEnv("WEATHER_KAFKA_TOPIC", "weather-updates"), "weather-redis-syncer"),
 		UserConsumer:     NewConsumer(getEnv("USER_KAFKA_TOPIC", "user-events"), "user-redis-syncer"),
 		ExchangeConsumer: NewConsumer(getEnv("EXCHANGE_KAFKA_TOPIC", "exchange-updates"), "exchange-redis-syncer"),
 		PopularConsumer:  NewConsumer("popular-requests", "popular-syncer"),
+		RequestConsumer:  NewConsumer(getEnv("REQUEST_EVENTS_KAFKA_TOPIC", "request-events"), "request-log-syncer"),
+		NotifyConsumer:   NewConsumer(getEnv("NOTIFICATIONS_KAFKA_TOPIC", "notifications"), "notification-dispatcher"),
 	}
 }
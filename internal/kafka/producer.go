@@ -13,6 +13,11 @@ import (
 type ProducerInterface interface {
 	PublishObjectAsync(key []byte, obj interface{})
 }
+
+// SyncProducer — публикация с подтверждением доставки
+type SyncProducer interface {
+	Publish(key, value []byte) error
+}
 type Producer struct {
 	topic  string
 	client *kgo.Client
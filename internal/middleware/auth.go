@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"service-info/internal/apierror"
+
 	"github.com/redis/go-redis/v9"
 )
 
@@ -19,13 +21,13 @@ func AuthRequired(redisClient *redis.Client) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			userIDStr := r.Header.Get("X-User-ID")
 			if userIDStr == "" {
-				http.Error(w, "X-User-ID header required", http.StatusUnauthorized)
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, "X-User-ID header required")
 				return
 			}
 
 			userID, err := strconv.ParseInt(userIDStr, 10, 64)
 			if err != nil {
-				http.Error(w, "Invalid X-User-ID", http.StatusBadRequest)
+				apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, "Invalid X-User-ID")
 				return
 			}
 
@@ -34,12 +36,12 @@ func AuthRequired(redisClient *redis.Client) func(http.Handler) http.Handler {
 			exists, err := redisClient.Exists(ctx, "user:"+userIDStr).Result()
 			if err != nil {
 				log.Printf("❌ Redis EXISTS error for user:%s: %v", userIDStr, err)
-				http.Error(w, "Internal error", http.StatusInternalServerError)
+				apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "Internal error")
 				return
 			}
 
 			if exists == 0 {
-				http.Error(w, "User not registered. Please use /auth in Telegram bot.", http.StatusUnauthorized)
+				apierror.Write(w, r, http.StatusUnauthorized, apierror.CodeUnauthorized, "User not registered. Please use /auth in Telegram bot.")
 				return
 			}
 
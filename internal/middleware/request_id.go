@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"service-info/internal/apierror"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestID берёт X-Request-ID клиента или генерирует новый, возвращает его
+// в ответе и кладёт в контекст — он попадает в тело ошибок и в логи
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > 128 {
+			buf := make([]byte, 8)
+			rand.Read(buf)
+			id = hex.EncodeToString(buf)
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(apierror.WithRequestID(r.Context(), id)))
+	})
+}
+
+// APIVersion помечает запросы ветки маршрутов версией API
+func APIVersion(version string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(apierror.WithVersion(r.Context(), version)))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AlertAbove    = "above"
+	AlertBelow    = "below"
+	AlertContains = "contains"
+)
+
+// Метрики погоды, на которые можно подписаться
+const (
+	WeatherMetricTemp      = "temp"
+	WeatherMetricFeelsLike = "feels_like"
+	WeatherMetricWind      = "wind"
+	WeatherMetricHumidity  = "humidity"
+	WeatherMetricCondition = "condition"
+)
+
+// ExchangeAlert — правило «курс base/target пересёк threshold в направлении direction».
+// LastRate — последний курс, на котором правило проверялось: по нему
+// определяется именно пересечение уровня, а не нахождение за ним.
+type ExchangeAlert struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"-"`
+	Base            string     `json:"base"`
+	Target          string     `json:"target"`
+	Direction       string     `json:"direction"`
+	Threshold       float64    `json:"threshold"`
+	CooldownSeconds int        `json:"cooldown_seconds"`
+	Enabled         bool       `json:"enabled"`
+	LastRate        *float64   `json:"last_rate,omitempty"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// WeatherAlert — правило «metric operator threshold» для города, например
+// «temp below -20» или «condition contains snow». Пока условие выполняется,
+// правило срабатывает не чаще одного раза за период PeriodSeconds.
+type WeatherAlert struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"-"`
+	City            string     `json:"city"`
+	Metric          string     `json:"metric"`
+	Operator        string     `json:"operator"`
+	Threshold       *float64   `json:"threshold,omitempty"`
+	Match           string     `json:"match,omitempty"`
+	PeriodSeconds   int        `json:"period_seconds"`
+	Enabled         bool       `json:"enabled"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// AlertEvent — сработавшее правило
+type AlertEvent struct {
+	ID        int64           `json:"id"`
+	RuleID    int64           `json:"rule_id"`
+	UserID    int64           `json:"-"`
+	Kind      string          `json:"kind"`
+	Message   string          `json:"message"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// DigestSchedule — когда присылать пользователю утреннюю сводку:
+// TimeOfDay ("08:00") в его часовом поясе Timezone ("Europe/Moscow").
+// LastSentDate — локальная дата последней отправки, не даёт отправить сводку дважды за день.
+type DigestSchedule struct {
+	UserID       int64     `json:"-"`
+	TimeOfDay    string    `json:"time_of_day"`
+	Timezone     string    `json:"timezone"`
+	Enabled      bool      `json:"enabled"`
+	LastSentDate string    `json:"last_sent_date,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	NotificationExchangeAlert = "exchange_alert"
+	NotificationWeatherAlert  = "weather_alert"
+	NotificationDigest        = "digest"
+)
+
+// Каналы доставки уведомлений
+const (
+	ChannelTelegram = "telegram"
+	ChannelWebhook  = "webhook"
+	ChannelEmail    = "email"
+)
+
+const (
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Notification — событие для пользователя в топике notifications.
+// ID уникален для события («alert:<id>») и служит ключом идемпотентной доставки.
+type Notification struct {
+	ID        string          `json:"id"`
+	EventID   int64           `json:"event_id,omitempty"`
+	UserID    int64           `json:"user_id"`
+	Kind      string          `json:"kind"`
+	Message   string          `json:"message"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NotificationChannel — куда пользователь хочет получать уведомления:
+// chat_id для Telegram, URL для webhook, адрес для email
+type NotificationChannel struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"-"`
+	Channel     string    `json:"channel"`
+	Destination string    `json:"destination"`
+	Secret      string    `json:"secret,omitempty"` // ключ подписи webhook, в API виден только при создании
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NotificationDelivery — запись журнала доставки уведомления в канал
+type NotificationDelivery struct {
+	ID             int64      `json:"id"`
+	NotificationID string     `json:"notification_id"`
+	UserID         int64      `json:"-"`
+	ChannelID      int64      `json:"channel_id"`
+	Channel        string     `json:"channel"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
@@ -1,6 +1,40 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type PopularRequest struct {
-	Type string   `json:"type"`
-	Args TaskArgs `json:"args"`
+	Type  string   `json:"type"`
+	Args  TaskArgs `json:"args"`
+	Count int      `json:"count"`
+	Score float64  `json:"score"`
+}
+
+// PopularityPolicy — как считать популярность запросов одного типа
+type PopularityPolicy struct {
+	Window   time.Duration `json:"window"`
+	TopN     int           `json:"top_n"`
+	MinCount int           `json:"min_count"`
+	// HalfLife > 0 включает экспоненциальное затухание веса старых запросов
+	HalfLife time.Duration `json:"half_life"`
+}
+
+// PopularSnapshot — рейтинг популярных запросов за окно, который
+// PopularPublisher складывает в Redis для публичного API
+type PopularSnapshot struct {
+	Window      string           `json:"window"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Items       []PopularRequest `json:"items"`
+}
+
+// PopularItem — позиция рейтинга вместе с текущим значением из кэша
+type PopularItem struct {
+	Rank  int             `json:"rank"`
+	Type  string          `json:"type"`
+	Args  TaskArgs        `json:"args"`
+	Count int             `json:"count"`
+	Score float64         `json:"score"`
+	Value json.RawMessage `json:"value"`
 }
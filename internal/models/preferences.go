@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type CurrencyPair struct {
+	Base   string `json:"base"`
+	Target string `json:"target"`
+}
+
+type UserPreferences struct {
+	UserID         int64          `json:"-"`
+	DefaultCity    string         `json:"default_city"`
+	FavoriteCities []string       `json:"favorite_cities"`
+	FavoritePairs  []CurrencyPair `json:"favorite_pairs"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// DashboardItem — значение из кэша или ошибка его получения
+type DashboardItem[T any] struct {
+	Data  *T     `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type Dashboard struct {
+	DefaultCity string                                 `json:"default_city,omitempty"`
+	Weather     map[string]DashboardItem[Weather]      `json:"weather"`
+	Exchange    map[string]DashboardItem[ExchangeRate] `json:"exchange"`
+}
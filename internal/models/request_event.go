@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RequestEvent — аналитическое событие об обработанном запросе пользователя
+type RequestEvent struct {
+	Type      string    `json:"type"`
+	Args      TaskArgs  `json:"args"`
+	UserID    int64     `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -13,8 +13,23 @@ type Task struct {
 	Args      TaskArgs  `json:"args"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Расписание: cron-выражение ("*/10 * * * *") или интервал ("@every 15m").
+	// Задачи без расписания только учитываются в популярности.
+	Schedule   string     `json:"schedule,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	NextRunAt  *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	LastStatus string     `json:"last_status,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
 }
 
+const (
+	TaskStatusPublished = "published"
+	TaskStatusFailed    = "failed"
+	TaskStatusSkipped   = "skipped"
+)
+
 type TaskArgs map[string]string
 
 // Value реализует driver.Valuer → позволяет сохранять в JSONB
@@ -4,6 +4,7 @@ import "time"
 
 type Weather struct {
 	City         string    `json:"city"`
+	Lang         string    `json:"lang,omitempty"` // язык condition
 	Temp         float64   `json:"temp_celsius"`
 	FeelsLike    float64   `json:"feels_like"`
 	Humidity     int       `json:"humidity"`
@@ -14,3 +15,19 @@ type Weather struct {
 	VisibilityKM float64   `json:"visibility_km"`
 	Updated      time.Time `json:"updated_at"`
 }
+
+// WeatherImperial — та же погода в имперских единицах. В кэше хранится
+// только метрическое наблюдение, этот вид строится из него при ответе.
+type WeatherImperial struct {
+	City            string    `json:"city"`
+	Lang            string    `json:"lang,omitempty"`
+	TempF           float64   `json:"temp_fahrenheit"`
+	FeelsLike       float64   `json:"feels_like"`
+	Humidity        int       `json:"humidity"`
+	Condition       string    `json:"condition"`
+	WindMPH         float64   `json:"wind_mph"`
+	PressureIn      float64   `json:"pressure_in"`
+	Cloud           int       `json:"cloud_percent"`
+	VisibilityMiles float64   `json:"visibility_miles"`
+	Updated         time.Time `json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"service-info/internal/models"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Channel доставляет уведомление в канал пользователя: по chat_id, URL или email
+type Channel interface {
+	Kind() string
+	Validate(destination string) error
+	Send(ctx context.Context, target models.NotificationChannel, n models.Notification) error
+}
+
+// statusError превращает HTTP-ответ в ошибку. 4xx (кроме 429) повторять
+// бессмысленно — адрес или запрос неверны, такие ошибки не ретраятся.
+func statusError(channel string, resp *http.Response, detail string) error {
+	err := fmt.Errorf("%s: status %d %s", channel, resp.StatusCode, detail)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return retry.Unrecoverable(err)
+	}
+	return err
+}
+
+func text(n models.Notification) string {
+	return "🔔 " + n.Message
+}
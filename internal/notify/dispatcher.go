@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Dispatcher доставляет уведомления во все включённые каналы пользователя.
+// Каждая доставка повторяется с экспоненциальной задержкой и пишется в
+// notification_deliveries; уже доставленное в канал уведомление не отправляется повторно.
+type Dispatcher struct {
+	repo     *repositories.NotificationRepository
+	channels map[string]Channel
+	attempts uint
+	delay    time.Duration
+}
+
+func NewDispatcher(repo *repositories.NotificationRepository, attempts uint, delay time.Duration, channels ...Channel) *Dispatcher {
+	d := &Dispatcher{
+		repo:     repo,
+		channels: make(map[string]Channel),
+		attempts: attempts,
+		delay:    delay,
+	}
+	for _, ch := range channels {
+		d.channels[ch.Kind()] = ch
+	}
+	return d
+}
+
+// Validate проверяет, что канал настроен на сервере и адрес для него корректен
+func (d *Dispatcher) Validate(kind, destination string) error {
+	ch, ok := d.channels[kind]
+	if !ok {
+		return fmt.Errorf("channel %q is not available", kind)
+	}
+	return ch.Validate(destination)
+}
+
+// Channels — виды каналов, настроенные на сервере
+func (d *Dispatcher) Channels() []string {
+	kinds := make([]string, 0, len(d.channels))
+	for kind := range d.channels {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Deliver отправляет уведомление во все каналы пользователя и возвращает
+// число успешных доставок
+func (d *Dispatcher) Deliver(ctx context.Context, n models.Notification) int {
+	targets, err := d.repo.ListChannels(ctx, n.UserID, true)
+	if err != nil {
+		log.Printf("Notification %s: channels of %d unavailable: %v", n.ID, n.UserID, err)
+		return 0
+	}
+
+	delivered := 0
+	for _, target := range targets {
+		if d.deliverTo(ctx, n, target) {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+func (d *Dispatcher) deliverTo(ctx context.Context, n models.Notification, target models.NotificationChannel) bool {
+	ch, ok := d.channels[target.Channel]
+	if !ok {
+		log.Printf("Notification %s: channel %s is not configured", n.ID, target.Channel)
+		return false
+	}
+
+	if done, err := d.repo.Delivered(ctx, n.ID, target.ID); err != nil {
+		log.Printf("Notification %s: delivery log unavailable: %v", n.ID, err)
+	} else if done {
+		return true
+	}
+
+	attempts := 0
+	err := retry.Do(
+		func() error {
+			attempts++
+			return ch.Send(ctx, target, n)
+		},
+		retry.Context(ctx),
+		retry.Attempts(d.attempts),
+		retry.Delay(d.delay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+	)
+
+	record := models.NotificationDelivery{
+		NotificationID: n.ID,
+		UserID:         n.UserID,
+		ChannelID:      target.ID,
+		Channel:        target.Channel,
+		Status:         models.DeliveryDelivered,
+		Attempts:       attempts,
+	}
+	if err != nil {
+		record.Status = models.DeliveryFailed
+		record.LastError = err.Error()
+		log.Printf("❌ Notification %s → %s #%d failed after %d attempts: %v", n.ID, target.Channel, target.ID, attempts, err)
+	} else {
+		now := time.Now()
+		record.DeliveredAt = &now
+		log.Printf("📨 Notification %s → %s #%d delivered", n.ID, target.Channel, target.ID)
+	}
+
+	if logErr := d.repo.LogDelivery(ctx, record); logErr != nil {
+		log.Printf("Notification %s: delivery log write failed: %v", n.ID, logErr)
+	}
+	return err == nil
+}
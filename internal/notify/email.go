@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"service-info/internal/models"
+)
+
+const defaultEmailTimeout = 15 * time.Second
+
+// Email отправляет письма через SMTP-сервер Addr (host:port).
+// Без Username письмо уходит без авторизации — так работают локальные relay и заглушки.
+// Вся отправка, от соединения до QUIT, укладывается в Timeout: зависший
+// SMTP-сервер не должен держать консьюмер уведомлений. Нулевой Timeout — 15 секунд.
+type Email struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+func NewEmail(addr, from, username, password string) *Email {
+	return &Email{Addr: addr, From: from, Username: username, Password: password, Timeout: defaultEmailTimeout}
+}
+
+func (*Email) Kind() string { return models.ChannelEmail }
+
+func (*Email) Validate(destination string) error {
+	addr, err := mail.ParseAddress(destination)
+	if err != nil || addr.Address != destination {
+		return fmt.Errorf("email destination must be a plain address")
+	}
+	return nil
+}
+
+func (e *Email) Send(ctx context.Context, target models.NotificationChannel, n models.Notification) error {
+	destination := target.Destination
+	host, _, _ := net.SplitHostPort(e.Addr)
+
+	msg := strings.Join([]string{
+		"From: " + e.From,
+		"To: " + destination,
+		"Subject: service-info: " + n.Kind,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		text(n),
+		"",
+	}, "\r\n")
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = defaultEmailTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	dialer := &net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", e.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// net/smtp не принимает контекст: дедлайн соединения ограничивает каждую
+	// команду, а отмена ctx обрывает ожидание сразу
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	err = e.send(conn, host, destination, []byte(msg))
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// send повторяет smtp.SendMail поверх уже открытого соединения
+func (e *Email) send(conn net.Conn, host, destination string, msg []byte) error {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if e.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", e.Username, e.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(destination); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
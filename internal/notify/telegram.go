@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"service-info/internal/models"
+)
+
+var telegramChatID = regexp.MustCompile(`^(-?\d+|@[A-Za-z0-9_]{5,})$`)
+
+// Telegram отправляет сообщения через Bot API sendMessage.
+// BaseURL настраивается, чтобы в тестах подменить api.telegram.org заглушкой.
+type Telegram struct {
+	BaseURL string
+	Token   string
+	Client  *http.Client
+}
+
+func NewTelegram(baseURL, token string) *Telegram {
+	if baseURL == "" {
+		baseURL = "https://api.telegram.org"
+	}
+	return &Telegram{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (*Telegram) Kind() string { return models.ChannelTelegram }
+
+func (*Telegram) Validate(destination string) error {
+	if !telegramChatID.MatchString(destination) {
+		return fmt.Errorf("telegram destination must be a chat id or @channel")
+	}
+	return nil
+}
+
+func (t *Telegram) Send(ctx context.Context, target models.NotificationChannel, n models.Notification) error {
+	body, err := json.Marshal(map[string]string{
+		"chat_id": target.Destination,
+		"text":    text(n),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.BaseURL+"/bot"+t.Token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK || !result.OK {
+		return statusError("telegram", resp, result.Description)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"service-info/internal/models"
+
+	"github.com/avast/retry-go/v4"
+)
+
+const (
+	SignatureHeader = "X-Signature-256"
+	TimestampHeader = "X-Signature-Timestamp"
+)
+
+const maxWebhookRedirects = 5
+
+var (
+	errInternalAddress = errors.New("webhook destination must not point to a loopback, private or link-local address")
+	errNoSecret        = errors.New("webhook channel has no signing secret, re-create it")
+)
+
+// Webhook отправляет уведомление POST-запросом с JSON-телом.
+// Подпись — HMAC-SHA256 от "<timestamp>.<body>" в заголовке X-Signature-256
+// на секрете канала: он выдаётся пользователю один раз при создании канала,
+// так что получатель проверяет только свои запросы. timestamp в подписи
+// защищает получателя от повторной отправки старых запросов.
+//
+// Адрес задаёт пользователь, поэтому запросы во внутреннюю сеть (Redis,
+// Postgres, метаданные облака) запрещены: при добавлении канала, при каждом
+// соединении (защита от DNS rebinding) и на каждом редиректе.
+type Webhook struct {
+	Client *http.Client
+	// AllowPrivate снимает запрет на внутренние адреса — только для тестов
+	AllowPrivate bool
+}
+
+func NewWebhook() *Webhook {
+	h := &Webhook{}
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: h.checkDial}
+	h.Client = &http.Client{
+		Timeout: 10 * time.Second,
+		// Без прокси: иначе проверка соединения видела бы адрес прокси, а не получателя
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxWebhookRedirects {
+				return fmt.Errorf("webhook: stopped after %d redirects", maxWebhookRedirects)
+			}
+			return h.Validate(req.URL.String())
+		},
+	}
+	return h
+}
+
+func (*Webhook) Kind() string { return models.ChannelWebhook }
+
+func (h *Webhook) Validate(destination string) error {
+	u, err := url.Parse(destination)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook destination must be an http(s) URL")
+	}
+	if h.AllowPrivate {
+		return nil
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if internalIP(ip) {
+			return errInternalAddress
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("webhook host %q cannot be resolved", host)
+	}
+	for _, addr := range addrs {
+		if internalIP(addr.IP) {
+			return errInternalAddress
+		}
+	}
+	return nil
+}
+
+// checkDial — net.Dialer.Control: проверяет уже разрешённый IP перед соединением
+func (h *Webhook) checkDial(network, address string, _ syscall.RawConn) error {
+	if h.AllowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || internalIP(ip) {
+		return errInternalAddress
+	}
+	return nil
+}
+
+func internalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+func (h *Webhook) Send(ctx context.Context, target models.NotificationChannel, n models.Notification) error {
+	// Каналы без секрета (созданные до его появления) подписать нечем — их нужно пересоздать
+	if target.Secret == "" {
+		return retry.Unrecoverable(errNoSecret)
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Destination, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(target.Secret, timestamp, body))
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError("webhook", resp, "")
+	}
+	return nil
+}
+
+// Sign — подпись тела webhook; получатель считает её так же и сравнивает
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+// Package openapi отдаёт спецификацию API (/openapi.json, /docs) и проверяет
+// по ней запросы и ответы — в тестах это ловит расхождения кода и документации.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"service-info/internal/apierror"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+// Load разбирает и проверяет встроенную спецификацию
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(specYAML)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+var (
+	specOnce sync.Once
+	specJSON []byte
+	specErr  error
+)
+
+// ServeSpec — GET /openapi.json
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	specOnce.Do(func() {
+		var doc *openapi3.T
+		if doc, specErr = Load(); specErr == nil {
+			specJSON, specErr = json.Marshal(doc)
+		}
+	})
+	if specErr != nil {
+		log.Printf("OpenAPI spec is invalid: %v", specErr)
+		apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(specJSON)
+}
+
+// ServeDocs — GET /docs, Swagger UI поверх /openapi.json
+func ServeDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsHTML))
+}
+
+const docsHTML = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+  <meta charset="utf-8">
+  <title>service-info API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
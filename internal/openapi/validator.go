@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"service-info/internal/apierror"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// streamingExtension помечает операции, ответ которых нельзя буферизовать (SSE, WebSocket)
+const streamingExtension = "x-streaming"
+
+const versionPrefix = "/v1"
+
+// Validator проверяет запросы и ответы по спецификации. Запрос с ошибкой
+// получает 400 и до хэндлера не доходит; расхождение ответа со спецификацией
+// передаётся в onError — тесты превращают его в t.Error.
+type Validator struct {
+	router  routers.Router
+	onError func(r *http.Request, err error)
+}
+
+func NewValidator(onError func(r *http.Request, err error)) (*Validator, error) {
+	doc, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	// Без servers маршруты сопоставляются с любым хостом, в том числе httptest;
+	// префикс версии снимается в FindRoute
+	doc.Servers = nil
+	for _, item := range doc.Paths.Map() {
+		item.Servers = nil
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{router: router, onError: onError}, nil
+}
+
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(withoutVersion(r))
+		if err != nil {
+			v.onError(r, fmt.Errorf("route is not documented: %w", err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				// Авторизацию проверяет middleware.AuthRequired
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			apierror.Write(w, r, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+			return
+		}
+
+		if route.Operation.Extensions[streamingExtension] != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+		})
+		if err != nil {
+			v.onError(r, err)
+		}
+	})
+}
+
+// withoutVersion — копия запроса без префикса /v1, по ней ищется операция спецификации
+func withoutVersion(r *http.Request) *http.Request {
+	path, ok := strings.CutPrefix(r.URL.Path, versionPrefix)
+	if !ok || !strings.HasPrefix(path, "/") {
+		return r
+	}
+	u := *r.URL
+	u.Path = path
+	u.RawPath = ""
+	clone := r.Clone(r.Context())
+	clone.URL = &u
+	return clone
+}
+
+// responseRecorder пропускает ответ клиенту и сохраняет копию тела для проверки
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
@@ -0,0 +1,4 @@
+// Package pb содержит код, сгенерированный из proto/.
+package pb
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=service-info/internal/pb --go-grpc_out=. --go-grpc_opt=module=service-info/internal/pb serviceinfo/v1/serviceinfo.proto
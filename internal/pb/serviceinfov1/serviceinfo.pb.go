@@ -0,0 +1,924 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: serviceinfo/v1/serviceinfo.proto
+
+package serviceinfov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Weather struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	City         string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	TempCelsius  float64                `protobuf:"fixed64,2,opt,name=temp_celsius,json=tempCelsius,proto3" json:"temp_celsius,omitempty"`
+	FeelsLike    float64                `protobuf:"fixed64,3,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	Humidity     int32                  `protobuf:"varint,4,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	Condition    string                 `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
+	WindKph      float64                `protobuf:"fixed64,6,opt,name=wind_kph,json=windKph,proto3" json:"wind_kph,omitempty"`
+	PressureMb   float64                `protobuf:"fixed64,7,opt,name=pressure_mb,json=pressureMb,proto3" json:"pressure_mb,omitempty"`
+	CloudPercent int32                  `protobuf:"varint,8,opt,name=cloud_percent,json=cloudPercent,proto3" json:"cloud_percent,omitempty"`
+	VisibilityKm float64                `protobuf:"fixed64,9,opt,name=visibility_km,json=visibilityKm,proto3" json:"visibility_km,omitempty"`
+	// RFC 3339
+	UpdatedAt     string `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Weather) Reset() {
+	*x = Weather{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Weather) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Weather) ProtoMessage() {}
+
+func (x *Weather) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Weather.ProtoReflect.Descriptor instead.
+func (*Weather) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Weather) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Weather) GetTempCelsius() float64 {
+	if x != nil {
+		return x.TempCelsius
+	}
+	return 0
+}
+
+func (x *Weather) GetFeelsLike() float64 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *Weather) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *Weather) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *Weather) GetWindKph() float64 {
+	if x != nil {
+		return x.WindKph
+	}
+	return 0
+}
+
+func (x *Weather) GetPressureMb() float64 {
+	if x != nil {
+		return x.PressureMb
+	}
+	return 0
+}
+
+func (x *Weather) GetCloudPercent() int32 {
+	if x != nil {
+		return x.CloudPercent
+	}
+	return 0
+}
+
+func (x *Weather) GetVisibilityKm() float64 {
+	if x != nil {
+		return x.VisibilityKm
+	}
+	return 0
+}
+
+func (x *Weather) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ExchangeRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target        string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Rate          float64                `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeRate) Reset() {
+	*x = ExchangeRate{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRate) ProtoMessage() {}
+
+func (x *ExchangeRate) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRate.ProtoReflect.Descriptor instead.
+func (*ExchangeRate) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ExchangeRate) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ExchangeRate) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserName      string                 `protobuf:"bytes,2,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	FirstName     string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName      string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *User) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *User) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *User) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *User) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+type GetWeatherRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Пустой city — город по умолчанию из настроек пользователя
+	City          string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWeatherRequest) Reset() {
+	*x = GetWeatherRequest{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWeatherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWeatherRequest) ProtoMessage() {}
+
+func (x *GetWeatherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWeatherRequest.ProtoReflect.Descriptor instead.
+func (*GetWeatherRequest) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetWeatherRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+type GetExchangeRateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Пустые base и target — первая избранная пара пользователя
+	Base          string `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Target        string `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExchangeRateRequest) Reset() {
+	*x = GetExchangeRateRequest{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExchangeRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExchangeRateRequest) ProtoMessage() {}
+
+func (x *GetExchangeRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExchangeRateRequest.ProtoReflect.Descriptor instead.
+func (*GetExchangeRateRequest) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetExchangeRateRequest) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *GetExchangeRateRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type CreateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserResponse) Reset() {
+	*x = CreateUserResponse{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserResponse) ProtoMessage() {}
+
+func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{6}
+}
+
+type GetPopularRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// weather, exchange или пусто — все типы
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// 1h, 24h или 7d; по умолчанию 24h
+	Window        string `protobuf:"bytes,2,opt,name=window,proto3" json:"window,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPopularRequest) Reset() {
+	*x = GetPopularRequest{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPopularRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPopularRequest) ProtoMessage() {}
+
+func (x *GetPopularRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPopularRequest.ProtoReflect.Descriptor instead.
+func (*GetPopularRequest) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPopularRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GetPopularRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+type PopularItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rank  int32                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Type  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Args  map[string]string      `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Count int64                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Score float64                `protobuf:"fixed64,5,opt,name=score,proto3" json:"score,omitempty"`
+	// Текущее значение из кэша в JSON, пусто если ключ истёк
+	Value         []byte `protobuf:"bytes,6,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PopularItem) Reset() {
+	*x = PopularItem{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PopularItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopularItem) ProtoMessage() {}
+
+func (x *PopularItem) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopularItem.ProtoReflect.Descriptor instead.
+func (*PopularItem) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PopularItem) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *PopularItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PopularItem) GetArgs() map[string]string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *PopularItem) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PopularItem) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *PopularItem) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type GetPopularResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Window string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	// RFC 3339, пусто если рейтинг ещё не посчитан
+	GeneratedAt   string         `protobuf:"bytes,2,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	Items         []*PopularItem `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPopularResponse) Reset() {
+	*x = GetPopularResponse{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPopularResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPopularResponse) ProtoMessage() {}
+
+func (x *GetPopularResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPopularResponse.ProtoReflect.Descriptor instead.
+func (*GetPopularResponse) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPopularResponse) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *GetPopularResponse) GetGeneratedAt() string {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return ""
+}
+
+func (x *GetPopularResponse) GetItems() []*PopularItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type WatchUpdatesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Weather []string               `protobuf:"bytes,1,rep,name=weather,proto3" json:"weather,omitempty"`
+	// Пары вида usd_eur
+	Exchange []string `protobuf:"bytes,2,rep,name=exchange,proto3" json:"exchange,omitempty"`
+	// Id последнего полученного обновления: пропущенные придут первыми
+	LastEventId   string `protobuf:"bytes,3,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUpdatesRequest) Reset() {
+	*x = WatchUpdatesRequest{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUpdatesRequest) ProtoMessage() {}
+
+func (x *WatchUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*WatchUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchUpdatesRequest) GetWeather() []string {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *WatchUpdatesRequest) GetExchange() []string {
+	if x != nil {
+		return x.Exchange
+	}
+	return nil
+}
+
+func (x *WatchUpdatesRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+type CacheUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key   string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Значение в JSON, как в Redis
+	Data          []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CacheUpdate) Reset() {
+	*x = CacheUpdate{}
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CacheUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheUpdate) ProtoMessage() {}
+
+func (x *CacheUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_serviceinfo_v1_serviceinfo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheUpdate.ProtoReflect.Descriptor instead.
+func (*CacheUpdate) Descriptor() ([]byte, []int) {
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CacheUpdate) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CacheUpdate) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CacheUpdate) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_serviceinfo_v1_serviceinfo_proto protoreflect.FileDescriptor
+
+const file_serviceinfo_v1_serviceinfo_proto_rawDesc = "" +
+	"\n" +
+	" serviceinfo/v1/serviceinfo.proto\x12\x0eserviceinfo.v1\"\xbe\x02\n" +
+	"\aWeather\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\x12!\n" +
+	"\ftemp_celsius\x18\x02 \x01(\x01R\vtempCelsius\x12\x1d\n" +
+	"\n" +
+	"feels_like\x18\x03 \x01(\x01R\tfeelsLike\x12\x1a\n" +
+	"\bhumidity\x18\x04 \x01(\x05R\bhumidity\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\x12\x19\n" +
+	"\bwind_kph\x18\x06 \x01(\x01R\awindKph\x12\x1f\n" +
+	"\vpressure_mb\x18\a \x01(\x01R\n" +
+	"pressureMb\x12#\n" +
+	"\rcloud_percent\x18\b \x01(\x05R\fcloudPercent\x12#\n" +
+	"\rvisibility_km\x18\t \x01(\x01R\fvisibilityKm\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\"m\n" +
+	"\fExchangeRate\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"x\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1b\n" +
+	"\tuser_name\x18\x02 \x01(\tR\buserName\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\"'\n" +
+	"\x11GetWeatherRequest\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\"D\n" +
+	"\x16GetExchangeRateRequest\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\"=\n" +
+	"\x11CreateUserRequest\x12(\n" +
+	"\x04user\x18\x01 \x01(\v2\x14.serviceinfo.v1.UserR\x04user\"\x14\n" +
+	"\x12CreateUserResponse\"?\n" +
+	"\x11GetPopularRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x16\n" +
+	"\x06window\x18\x02 \x01(\tR\x06window\"\xeb\x01\n" +
+	"\vPopularItem\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x05R\x04rank\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x129\n" +
+	"\x04args\x18\x03 \x03(\v2%.serviceinfo.v1.PopularItem.ArgsEntryR\x04args\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x03R\x05count\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x01R\x05score\x12\x14\n" +
+	"\x05value\x18\x06 \x01(\fR\x05value\x1a7\n" +
+	"\tArgsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x82\x01\n" +
+	"\x12GetPopularResponse\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12!\n" +
+	"\fgenerated_at\x18\x02 \x01(\tR\vgeneratedAt\x121\n" +
+	"\x05items\x18\x03 \x03(\v2\x1b.serviceinfo.v1.PopularItemR\x05items\"o\n" +
+	"\x13WatchUpdatesRequest\x12\x18\n" +
+	"\aweather\x18\x01 \x03(\tR\aweather\x12\x1a\n" +
+	"\bexchange\x18\x02 \x03(\tR\bexchange\x12\"\n" +
+	"\rlast_event_id\x18\x03 \x01(\tR\vlastEventId\"C\n" +
+	"\vCacheUpdate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data2\xae\x03\n" +
+	"\vServiceInfo\x12H\n" +
+	"\n" +
+	"GetWeather\x12!.serviceinfo.v1.GetWeatherRequest\x1a\x17.serviceinfo.v1.Weather\x12W\n" +
+	"\x0fGetExchangeRate\x12&.serviceinfo.v1.GetExchangeRateRequest\x1a\x1c.serviceinfo.v1.ExchangeRate\x12S\n" +
+	"\n" +
+	"CreateUser\x12!.serviceinfo.v1.CreateUserRequest\x1a\".serviceinfo.v1.CreateUserResponse\x12S\n" +
+	"\n" +
+	"GetPopular\x12!.serviceinfo.v1.GetPopularRequest\x1a\".serviceinfo.v1.GetPopularResponse\x12R\n" +
+	"\fWatchUpdates\x12#.serviceinfo.v1.WatchUpdatesRequest\x1a\x1b.serviceinfo.v1.CacheUpdate0\x01B6Z4service-info/internal/pb/serviceinfov1;serviceinfov1b\x06proto3"
+
+var (
+	file_serviceinfo_v1_serviceinfo_proto_rawDescOnce sync.Once
+	file_serviceinfo_v1_serviceinfo_proto_rawDescData []byte
+)
+
+func file_serviceinfo_v1_serviceinfo_proto_rawDescGZIP() []byte {
+	file_serviceinfo_v1_serviceinfo_proto_rawDescOnce.Do(func() {
+		file_serviceinfo_v1_serviceinfo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_serviceinfo_v1_serviceinfo_proto_rawDesc), len(file_serviceinfo_v1_serviceinfo_proto_rawDesc)))
+	})
+	return file_serviceinfo_v1_serviceinfo_proto_rawDescData
+}
+
+var file_serviceinfo_v1_serviceinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_serviceinfo_v1_serviceinfo_proto_goTypes = []any{
+	(*Weather)(nil),                // 0: serviceinfo.v1.Weather
+	(*ExchangeRate)(nil),           // 1: serviceinfo.v1.ExchangeRate
+	(*User)(nil),                   // 2: serviceinfo.v1.User
+	(*GetWeatherRequest)(nil),      // 3: serviceinfo.v1.GetWeatherRequest
+	(*GetExchangeRateRequest)(nil), // 4: serviceinfo.v1.GetExchangeRateRequest
+	(*CreateUserRequest)(nil),      // 5: serviceinfo.v1.CreateUserRequest
+	(*CreateUserResponse)(nil),     // 6: serviceinfo.v1.CreateUserResponse
+	(*GetPopularRequest)(nil),      // 7: serviceinfo.v1.GetPopularRequest
+	(*PopularItem)(nil),            // 8: serviceinfo.v1.PopularItem
+	(*GetPopularResponse)(nil),     // 9: serviceinfo.v1.GetPopularResponse
+	(*WatchUpdatesRequest)(nil),    // 10: serviceinfo.v1.WatchUpdatesRequest
+	(*CacheUpdate)(nil),            // 11: serviceinfo.v1.CacheUpdate
+	nil,                            // 12: serviceinfo.v1.PopularItem.ArgsEntry
+}
+var file_serviceinfo_v1_serviceinfo_proto_depIdxs = []int32{
+	2,  // 0: serviceinfo.v1.CreateUserRequest.user:type_name -> serviceinfo.v1.User
+	12, // 1: serviceinfo.v1.PopularItem.args:type_name -> serviceinfo.v1.PopularItem.ArgsEntry
+	8,  // 2: serviceinfo.v1.GetPopularResponse.items:type_name -> serviceinfo.v1.PopularItem
+	3,  // 3: serviceinfo.v1.ServiceInfo.GetWeather:input_type -> serviceinfo.v1.GetWeatherRequest
+	4,  // 4: serviceinfo.v1.ServiceInfo.GetExchangeRate:input_type -> serviceinfo.v1.GetExchangeRateRequest
+	5,  // 5: serviceinfo.v1.ServiceInfo.CreateUser:input_type -> serviceinfo.v1.CreateUserRequest
+	7,  // 6: serviceinfo.v1.ServiceInfo.GetPopular:input_type -> serviceinfo.v1.GetPopularRequest
+	10, // 7: serviceinfo.v1.ServiceInfo.WatchUpdates:input_type -> serviceinfo.v1.WatchUpdatesRequest
+	0,  // 8: serviceinfo.v1.ServiceInfo.GetWeather:output_type -> serviceinfo.v1.Weather
+	1,  // 9: serviceinfo.v1.ServiceInfo.GetExchangeRate:output_type -> serviceinfo.v1.ExchangeRate
+	6,  // 10: serviceinfo.v1.ServiceInfo.CreateUser:output_type -> serviceinfo.v1.CreateUserResponse
+	9,  // 11: serviceinfo.v1.ServiceInfo.GetPopular:output_type -> serviceinfo.v1.GetPopularResponse
+	11, // 12: serviceinfo.v1.ServiceInfo.WatchUpdates:output_type -> serviceinfo.v1.CacheUpdate
+	8,  // [8:13] is the sub-list for method output_type
+	3,  // [3:8] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_serviceinfo_v1_serviceinfo_proto_init() }
+func file_serviceinfo_v1_serviceinfo_proto_init() {
+	if File_serviceinfo_v1_serviceinfo_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_serviceinfo_v1_serviceinfo_proto_rawDesc), len(file_serviceinfo_v1_serviceinfo_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_serviceinfo_v1_serviceinfo_proto_goTypes,
+		DependencyIndexes: file_serviceinfo_v1_serviceinfo_proto_depIdxs,
+		MessageInfos:      file_serviceinfo_v1_serviceinfo_proto_msgTypes,
+	}.Build()
+	File_serviceinfo_v1_serviceinfo_proto = out.File
+	file_serviceinfo_v1_serviceinfo_proto_goTypes = nil
+	file_serviceinfo_v1_serviceinfo_proto_depIdxs = nil
+}
@@ -0,0 +1,287 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: serviceinfo/v1/serviceinfo.proto
+
+package serviceinfov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ServiceInfo_GetWeather_FullMethodName      = "/serviceinfo.v1.ServiceInfo/GetWeather"
+	ServiceInfo_GetExchangeRate_FullMethodName = "/serviceinfo.v1.ServiceInfo/GetExchangeRate"
+	ServiceInfo_CreateUser_FullMethodName      = "/serviceinfo.v1.ServiceInfo/CreateUser"
+	ServiceInfo_GetPopular_FullMethodName      = "/serviceinfo.v1.ServiceInfo/GetPopular"
+	ServiceInfo_WatchUpdates_FullMethodName    = "/serviceinfo.v1.ServiceInfo/WatchUpdates"
+)
+
+// ServiceInfoClient is the client API for ServiceInfo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ServiceInfo — те же операции, что и HTTP API, для других Go-сервисов.
+// Авторизация — метаданные x-user-id, как заголовок X-User-ID в HTTP.
+type ServiceInfoClient interface {
+	GetWeather(ctx context.Context, in *GetWeatherRequest, opts ...grpc.CallOption) (*Weather, error)
+	GetExchangeRate(ctx context.Context, in *GetExchangeRateRequest, opts ...grpc.CallOption) (*ExchangeRate, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	GetPopular(ctx context.Context, in *GetPopularRequest, opts ...grpc.CallOption) (*GetPopularResponse, error)
+	// WatchUpdates присылает новые значения ключей кэша по мере того,
+	// как воркеры записывают их в Redis.
+	WatchUpdates(ctx context.Context, in *WatchUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CacheUpdate], error)
+}
+
+type serviceInfoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewServiceInfoClient(cc grpc.ClientConnInterface) ServiceInfoClient {
+	return &serviceInfoClient{cc}
+}
+
+func (c *serviceInfoClient) GetWeather(ctx context.Context, in *GetWeatherRequest, opts ...grpc.CallOption) (*Weather, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Weather)
+	err := c.cc.Invoke(ctx, ServiceInfo_GetWeather_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceInfoClient) GetExchangeRate(ctx context.Context, in *GetExchangeRateRequest, opts ...grpc.CallOption) (*ExchangeRate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExchangeRate)
+	err := c.cc.Invoke(ctx, ServiceInfo_GetExchangeRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceInfoClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateUserResponse)
+	err := c.cc.Invoke(ctx, ServiceInfo_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceInfoClient) GetPopular(ctx context.Context, in *GetPopularRequest, opts ...grpc.CallOption) (*GetPopularResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPopularResponse)
+	err := c.cc.Invoke(ctx, ServiceInfo_GetPopular_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceInfoClient) WatchUpdates(ctx context.Context, in *WatchUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CacheUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ServiceInfo_ServiceDesc.Streams[0], ServiceInfo_WatchUpdates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUpdatesRequest, CacheUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ServiceInfo_WatchUpdatesClient = grpc.ServerStreamingClient[CacheUpdate]
+
+// ServiceInfoServer is the server API for ServiceInfo service.
+// All implementations must embed UnimplementedServiceInfoServer
+// for forward compatibility.
+//
+// ServiceInfo — те же операции, что и HTTP API, для других Go-сервисов.
+// Авторизация — метаданные x-user-id, как заголовок X-User-ID в HTTP.
+type ServiceInfoServer interface {
+	GetWeather(context.Context, *GetWeatherRequest) (*Weather, error)
+	GetExchangeRate(context.Context, *GetExchangeRateRequest) (*ExchangeRate, error)
+	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
+	GetPopular(context.Context, *GetPopularRequest) (*GetPopularResponse, error)
+	// WatchUpdates присылает новые значения ключей кэша по мере того,
+	// как воркеры записывают их в Redis.
+	WatchUpdates(*WatchUpdatesRequest, grpc.ServerStreamingServer[CacheUpdate]) error
+	mustEmbedUnimplementedServiceInfoServer()
+}
+
+// UnimplementedServiceInfoServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedServiceInfoServer struct{}
+
+func (UnimplementedServiceInfoServer) GetWeather(context.Context, *GetWeatherRequest) (*Weather, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWeather not implemented")
+}
+func (UnimplementedServiceInfoServer) GetExchangeRate(context.Context, *GetExchangeRateRequest) (*ExchangeRate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExchangeRate not implemented")
+}
+func (UnimplementedServiceInfoServer) CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedServiceInfoServer) GetPopular(context.Context, *GetPopularRequest) (*GetPopularResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPopular not implemented")
+}
+func (UnimplementedServiceInfoServer) WatchUpdates(*WatchUpdatesRequest, grpc.ServerStreamingServer[CacheUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUpdates not implemented")
+}
+func (UnimplementedServiceInfoServer) mustEmbedUnimplementedServiceInfoServer() {}
+func (UnimplementedServiceInfoServer) testEmbeddedByValue()                     {}
+
+// UnsafeServiceInfoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ServiceInfoServer will
+// result in compilation errors.
+type UnsafeServiceInfoServer interface {
+	mustEmbedUnimplementedServiceInfoServer()
+}
+
+func RegisterServiceInfoServer(s grpc.ServiceRegistrar, srv ServiceInfoServer) {
+	// If the following call pancis, it indicates UnimplementedServiceInfoServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ServiceInfo_ServiceDesc, srv)
+}
+
+func _ServiceInfo_GetWeather_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWeatherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceInfoServer).GetWeather(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceInfo_GetWeather_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceInfoServer).GetWeather(ctx, req.(*GetWeatherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceInfo_GetExchangeRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExchangeRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceInfoServer).GetExchangeRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceInfo_GetExchangeRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceInfoServer).GetExchangeRate(ctx, req.(*GetExchangeRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceInfo_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceInfoServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceInfo_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceInfoServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceInfo_GetPopular_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPopularRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceInfoServer).GetPopular(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServiceInfo_GetPopular_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceInfoServer).GetPopular(ctx, req.(*GetPopularRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServiceInfo_WatchUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServiceInfoServer).WatchUpdates(m, &grpc.GenericServerStream[WatchUpdatesRequest, CacheUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ServiceInfo_WatchUpdatesServer = grpc.ServerStreamingServer[CacheUpdate]
+
+// ServiceInfo_ServiceDesc is the grpc.ServiceDesc for ServiceInfo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ServiceInfo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "serviceinfo.v1.ServiceInfo",
+	HandlerType: (*ServiceInfoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetWeather",
+			Handler:    _ServiceInfo_GetWeather_Handler,
+		},
+		{
+			MethodName: "GetExchangeRate",
+			Handler:    _ServiceInfo_GetExchangeRate_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _ServiceInfo_CreateUser_Handler,
+		},
+		{
+			MethodName: "GetPopular",
+			Handler:    _ServiceInfo_GetPopular_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUpdates",
+			Handler:       _ServiceInfo_WatchUpdates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "serviceinfo/v1/serviceinfo.proto",
+}
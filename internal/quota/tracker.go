@@ -0,0 +1,218 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ProviderWeatherAPI   = "weatherapi"
+	ProviderFreeCurrency = "freecurrencyapi"
+)
+
+// TaskProviders — какой провайдер обслуживает задачи каждого типа
+var TaskProviders = map[string]string{
+	"weather":  ProviderWeatherAPI,
+	"exchange": ProviderFreeCurrency,
+}
+
+type Priority int
+
+const (
+	// PriorityEssential — запросы пользователей (промах кэша), тратят бюджет до нуля
+	PriorityEssential Priority = iota
+	// PriorityBackground — префетч популярных запросов, не трогает резерв
+	PriorityBackground
+)
+
+var (
+	ErrExhausted = errors.New("upstream quota exhausted")
+	ErrReserved  = errors.New("upstream quota reserved for essential requests")
+)
+
+// Budget — лимиты вызовов провайдера; 0 означает «без ограничений»
+type Budget struct {
+	Daily   int
+	Monthly int
+}
+
+type Usage struct {
+	Provider          string `json:"provider"`
+	DailyUsed         int64  `json:"daily_used"`
+	DailyLimit        int    `json:"daily_limit"`
+	DailyRemaining    int64  `json:"daily_remaining"`
+	MonthlyUsed       int64  `json:"monthly_used"`
+	MonthlyLimit      int    `json:"monthly_limit"`
+	MonthlyRemaining  int64  `json:"monthly_remaining"`
+	ReservePercent    int    `json:"reserve_percent"`
+	BackgroundAllowed bool   `json:"background_allowed"`
+}
+
+// acquireScript атомарно проверяет оба счётчика и увеличивает их.
+// Возвращает 0 — разрешено, 1 — бюджет исчерпан, 2 — остался только резерв.
+var acquireScript = redis.NewScript(`
+local day = tonumber(redis.call('GET', KEYS[1]) or '0')
+local month = tonumber(redis.call('GET', KEYS[2]) or '0')
+local dayLimit = tonumber(ARGV[1])
+local monthLimit = tonumber(ARGV[2])
+local dayReserve = tonumber(ARGV[3])
+local monthReserve = tonumber(ARGV[4])
+
+if (dayLimit > 0 and day + 1 > dayLimit) or (monthLimit > 0 and month + 1 > monthLimit) then
+	return 1
+end
+if (dayLimit > 0 and day + 1 > dayLimit - dayReserve) or (monthLimit > 0 and month + 1 > monthLimit - monthReserve) then
+	return 2
+end
+
+redis.call('INCR', KEYS[1])
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+redis.call('INCR', KEYS[2])
+redis.call('EXPIRE', KEYS[2], ARGV[6])
+return 0
+`)
+
+// Tracker ведёт общий для всех реплик учёт вызовов внешних API в Redis.
+// Nil-трекер ничего не ограничивает.
+type Tracker struct {
+	redis          *redis.Client
+	budgets        map[string]Budget
+	reservePercent int
+	now            func() time.Time
+}
+
+func NewTracker(redisClient *redis.Client, budgets map[string]Budget, reservePercent int) *Tracker {
+	return &Tracker{
+		redis:          redisClient,
+		budgets:        budgets,
+		reservePercent: reservePercent,
+		now:            time.Now,
+	}
+}
+
+// Acquire списывает один вызов из бюджета провайдера.
+// Ошибки Redis не блокируют запросы — учёт в этом случае пропускается.
+func (t *Tracker) Acquire(ctx context.Context, provider string, prio Priority) error {
+	if t == nil {
+		return nil
+	}
+	budget, ok := t.budgets[provider]
+	if !ok || (budget.Daily <= 0 && budget.Monthly <= 0) {
+		return nil
+	}
+
+	dayReserve, monthReserve := 0, 0
+	if prio == PriorityBackground {
+		dayReserve = budget.Daily * t.reservePercent / 100
+		monthReserve = budget.Monthly * t.reservePercent / 100
+	}
+
+	dayKey, monthKey := t.keys(provider)
+	res, err := acquireScript.Run(ctx, t.redis,
+		[]string{dayKey, monthKey},
+		budget.Daily, budget.Monthly, dayReserve, monthReserve,
+		int((48 * time.Hour).Seconds()), int((32 * 24 * time.Hour).Seconds()),
+	).Int()
+	if err != nil {
+		log.Printf("⚠️ Quota check failed for %s: %v", provider, err)
+		return nil
+	}
+
+	switch res {
+	case 1:
+		return fmt.Errorf("%s: %w", provider, ErrExhausted)
+	case 2:
+		return fmt.Errorf("%s: %w", provider, ErrReserved)
+	}
+	return nil
+}
+
+// Allows проверяет, хватит ли бюджета на вызов, ничего не списывая
+func (t *Tracker) Allows(ctx context.Context, provider string, prio Priority) bool {
+	if t == nil {
+		return true
+	}
+	usage, err := t.Usage(ctx, provider)
+	if err != nil {
+		log.Printf("⚠️ Quota usage read failed for %s: %v", provider, err)
+		return true
+	}
+	if prio == PriorityBackground {
+		return usage.BackgroundAllowed
+	}
+	return (usage.DailyLimit <= 0 || usage.DailyRemaining > 0) &&
+		(usage.MonthlyLimit <= 0 || usage.MonthlyRemaining > 0)
+}
+
+func (t *Tracker) Usage(ctx context.Context, provider string) (Usage, error) {
+	budget := t.budgets[provider]
+	dayKey, monthKey := t.keys(provider)
+
+	vals, err := t.redis.MGet(ctx, dayKey, monthKey).Result()
+	if err != nil {
+		return Usage{}, err
+	}
+	day, month := parseCounter(vals[0]), parseCounter(vals[1])
+
+	usage := Usage{
+		Provider:       provider,
+		DailyUsed:      day,
+		DailyLimit:     budget.Daily,
+		MonthlyUsed:    month,
+		MonthlyLimit:   budget.Monthly,
+		ReservePercent: t.reservePercent,
+	}
+	usage.DailyRemaining = remaining(budget.Daily, day)
+	usage.MonthlyRemaining = remaining(budget.Monthly, month)
+	usage.BackgroundAllowed =
+		(budget.Daily <= 0 || usage.DailyRemaining > int64(budget.Daily*t.reservePercent/100)) &&
+			(budget.Monthly <= 0 || usage.MonthlyRemaining > int64(budget.Monthly*t.reservePercent/100))
+	return usage, nil
+}
+
+// Providers возвращает состояние бюджета по всем настроенным провайдерам
+func (t *Tracker) Providers(ctx context.Context) ([]Usage, error) {
+	result := make([]Usage, 0, len(t.budgets))
+	for _, provider := range []string{ProviderWeatherAPI, ProviderFreeCurrency} {
+		if _, ok := t.budgets[provider]; !ok {
+			continue
+		}
+		usage, err := t.Usage(ctx, provider)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, usage)
+	}
+	return result, nil
+}
+
+func (t *Tracker) keys(provider string) (string, string) {
+	now := t.now().UTC()
+	return "quota:" + provider + ":d:" + now.Format("20060102"),
+		"quota:" + provider + ":m:" + now.Format("200601")
+}
+
+func parseCounter(v interface{}) int64 {
+	s, ok := v.(string)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+func remaining(limit int, used int64) int64 {
+	if limit <= 0 {
+		return -1
+	}
+	if left := int64(limit) - used; left > 0 {
+		return left
+	}
+	return 0
+}
@@ -4,11 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"service-info/internal/models"
+
+	"github.com/lib/pq"
 )
 
+var ErrTaskNotFound = errors.New("task not found")
+
+// TaskFilter — условия выборки задач; пустые поля не ограничивают выборку
+type TaskFilter struct {
+	Title  string
+	Args   models.TaskArgs
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+	Offset int
+}
+
 type AdminRepository struct {
 	db *sql.DB
 }
@@ -21,44 +39,308 @@ func (r *AdminRepository) DB() *sql.DB {
 	return r.db
 }
 
-func (r *AdminRepository) Save(ctx context.Context, task models.Task) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO scheduled_tasks (title, args, created_at)
-		VALUES ($1, $2, $3)
-	`, task.Title, task.Args, task.CreatedAt)
-	return err
-}
-
-func (r *AdminRepository) GetTopRequests(ctx context.Context) ([]models.PopularRequest, error) {
-	const sqlQuery = `
-SELECT * FROM (
-    SELECT 'weather' AS type,
-           jsonb_build_object('city', args->>'city') AS args,
-           COUNT(*) AS cnt
-    FROM scheduled_tasks
-    WHERE title = 'weather' 
-      AND created_at >= NOW() - INTERVAL '24 hours'
-      AND args ? 'city'
-    GROUP BY args->>'city'
-    ORDER BY cnt DESC
-    LIMIT 5
-) AS weather_top
-UNION ALL
-SELECT * FROM (
-    SELECT 'exchange' AS type, 
-           jsonb_build_object('base', args->>'base', 'target', args->>'target') AS args,
-           COUNT(*) AS cnt
-    FROM scheduled_tasks
-    WHERE title = 'exchange'
-      AND created_at >= NOW() - INTERVAL '24 hours'
-      AND args ? 'base' AND args ? 'target'
-    GROUP BY args->>'base', args->>'target'
-    ORDER BY cnt DESC
-    LIMIT 5
-) AS exchange_top;
-`
-
-	rows, err := r.db.QueryContext(ctx, sqlQuery)
+// Save создаёт задачу и возвращает её id
+func (r *AdminRepository) Save(ctx context.Context, task models.Task) (int, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO scheduled_tasks (title, args, created_at, updated_at, schedule, enabled, next_run_at)
+		VALUES ($1, $2, $3, $3, NULLIF($4, ''), $5, $6)
+		RETURNING id
+	`, task.Title, task.Args, task.CreatedAt, task.Schedule, task.Enabled, task.NextRunAt).Scan(&id)
+	return id, err
+}
+
+// List возвращает страницу задач и общее число задач под фильтром
+func (r *AdminRepository) List(ctx context.Context, filter TaskFilter) ([]models.Task, int, error) {
+	where, args := filter.where()
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM scheduled_tasks" + where
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	listQuery := fmt.Sprintf(`
+		SELECT `+taskColumns+`
+		FROM scheduled_tasks%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	return tasks, total, err
+}
+
+func (r *AdminRepository) GetByID(ctx context.Context, id int) (*models.Task, error) {
+	task, err := scanTask(r.db.QueryRowContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM scheduled_tasks
+		WHERE id = $1
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// UpdateSchedule меняет расписание и флаг enabled задачи
+func (r *AdminRepository) UpdateSchedule(ctx context.Context, id int, schedule string, enabled bool, nextRunAt *time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_tasks
+		SET schedule = NULLIF($2, ''), enabled = $3, next_run_at = $4, updated_at = NOW()
+		WHERE id = $1
+	`, id, schedule, enabled, nextRunAt)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// ClaimDue забирает задачи, срок запуска которых наступил, и сразу сдвигает
+// их next_run_at на следующий запуск. FOR UPDATE SKIP LOCKED не даёт
+// двум экземплярам планировщика взять одну и ту же задачу, а term —
+// бывшему лидеру забрать их после смены лидерства (ErrStaleTerm). Задачи,
+// для которых next вернул ошибку (битое расписание), выключаются с
+// last_status failed и в результат не попадают.
+func (r *AdminRepository) ClaimDue(ctx context.Context, term int64, now time.Time, limit int, next func(models.Task) (*time.Time, error)) ([]models.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := fence(ctx, tx, term); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM scheduled_tasks
+		WHERE enabled AND schedule IS NOT NULL AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := scanTasks(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := tasks[:0]
+	for _, task := range tasks {
+		nextRun, nextErr := next(task)
+		if nextErr != nil {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE scheduled_tasks
+				SET enabled = FALSE, next_run_at = NULL,
+					last_run_at = $2, last_status = $3, last_error = $4
+				WHERE id = $1
+			`, task.ID, now, models.TaskStatusFailed, nextErr.Error()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE scheduled_tasks SET next_run_at = $2 WHERE id = $1
+		`, task.ID, nextRun); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, task)
+	}
+
+	return claimed, tx.Commit()
+}
+
+// RecordRun сохраняет результат запуска задачи, если срок лидерства term
+// ещё не сменился
+func (r *AdminRepository) RecordRun(ctx context.Context, term int64, id int, runAt time.Time, status string, runErr error) error {
+	var errText string
+	if runErr != nil {
+		errText = runErr.Error()
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fence(ctx, tx, term); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE scheduled_tasks
+		SET last_run_at = $2, last_status = $3, last_error = NULLIF($4, '')
+		WHERE id = $1
+	`, id, runAt, status, errText); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *AdminRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM scheduled_tasks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// DeleteMatching удаляет задачи с указанным title и/или созданные раньше olderThan.
+// Пустой фильтр запрещён, чтобы случайно не очистить всю таблицу.
+func (r *AdminRepository) DeleteMatching(ctx context.Context, title string, olderThan time.Time) (int64, error) {
+	if title == "" && olderThan.IsZero() {
+		return 0, fmt.Errorf("bulk delete requires title or age")
+	}
+	where, args := TaskFilter{Title: title, Until: olderThan}.where()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM scheduled_tasks"+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const taskColumns = `id, title, args, created_at, updated_at,
+		COALESCE(schedule, ''), enabled, next_run_at, last_run_at,
+		COALESCE(last_status, ''), COALESCE(last_error, '')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(row rowScanner) (*models.Task, error) {
+	var task models.Task
+	var nextRun, lastRun sql.NullTime
+	err := row.Scan(
+		&task.ID, &task.Title, &task.Args, &task.CreatedAt, &task.UpdatedAt,
+		&task.Schedule, &task.Enabled, &nextRun, &lastRun,
+		&task.LastStatus, &task.LastError,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if nextRun.Valid {
+		task.NextRunAt = &nextRun.Time
+	}
+	if lastRun.Valid {
+		task.LastRunAt = &lastRun.Time
+	}
+	return &task, nil
+}
+
+func scanTasks(rows *sql.Rows) ([]models.Task, error) {
+	tasks := make([]models.Task, 0)
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+	return tasks, rows.Err()
+}
+
+func (f TaskFilter) where() (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.Title != "" {
+		add("title = $%d", f.Title)
+	}
+	if len(f.Args) > 0 {
+		add("args @> $%d::jsonb", f.Args)
+	}
+	if !f.Since.IsZero() {
+		add("created_at >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("created_at < $%d", f.Until)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// GetTopRequests возвращает топ запросов типа taskType по политике policy.
+// Запросы группируются по keyArgs — аргументам, определяющим идентичность
+// запроса (city для погоды, base+target для курсов). При policy.HalfLife > 0
+// каждый сигнал весит exp(-ln2 * age / halfLife), и свежие запросы
+// поднимаются выше старых.
+func (r *AdminRepository) GetTopRequests(ctx context.Context, taskType string, keyArgs []string, policy models.PopularityPolicy) ([]models.PopularRequest, error) {
+	if len(keyArgs) == 0 {
+		return nil, fmt.Errorf("task type %s has no key args", taskType)
+	}
+
+	now := time.Now()
+	params := []interface{}{taskType, now.Add(-policy.Window), pq.Array(keyArgs), policy.MinCount, policy.TopN}
+
+	pairs := make([]string, 0, len(keyArgs))
+	for _, k := range keyArgs {
+		params = append(params, k)
+		pairs = append(pairs, fmt.Sprintf("$%[1]d::text, args->>$%[1]d::text", len(params)))
+	}
+
+	score := "COUNT(*)::float8"
+	if policy.HalfLife > 0 {
+		params = append(params, now, policy.HalfLife.Seconds())
+		score = fmt.Sprintf("SUM(EXP(-LN(2) * EXTRACT(EPOCH FROM ($%d - created_at)) / $%d))", len(params)-1, len(params))
+	}
+
+	// Сигналы популярности: реальные запросы пользователей и задачи из админки
+	sqlQuery := fmt.Sprintf(`
+WITH signals AS (
+    SELECT title, args, created_at FROM scheduled_tasks
+    UNION ALL
+    SELECT type AS title, args, created_at FROM request_log
+)
+SELECT jsonb_build_object(%s) AS key_args,
+       COUNT(*) AS cnt,
+       %s AS score
+FROM signals
+WHERE title = $1
+  AND created_at >= $2
+  AND args ?& $3
+GROUP BY 1
+HAVING COUNT(*) >= $4
+ORDER BY score DESC, cnt DESC
+LIMIT $5`, strings.Join(pairs, ", "), score)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, params...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,11 +348,11 @@ SELECT * FROM (
 
 	var topRequests []models.PopularRequest
 	for rows.Next() {
-		var typ string
 		var argsJSON []byte
 		var cnt int
+		var score float64
 
-		if err := rows.Scan(&typ, &argsJSON, &cnt); err != nil {
+		if err := rows.Scan(&argsJSON, &cnt, &score); err != nil {
 			return nil, err
 		}
 
@@ -81,8 +363,10 @@ SELECT * FROM (
 		}
 
 		topRequests = append(topRequests, models.PopularRequest{
-			Type: typ,
-			Args: args,
+			Type:  taskType,
+			Args:  args,
+			Count: cnt,
+			Score: score,
 		})
 	}
 
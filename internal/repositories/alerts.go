@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"service-info/internal/models"
+)
+
+var ErrAlertNotFound = errors.New("alert not found")
+
+type AlertRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+func (r *AlertRepository) CreateExchange(ctx context.Context, alert models.ExchangeAlert) (*models.ExchangeAlert, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO exchange_alerts (user_id, base, target, direction, threshold, cooldown_seconds, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, alert.UserID, alert.Base, alert.Target, alert.Direction, alert.Threshold, alert.CooldownSeconds, alert.Enabled,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *AlertRepository) ListExchange(ctx context.Context, userID int64) ([]models.ExchangeAlert, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+exchangeAlertColumns+`
+		FROM exchange_alerts
+		WHERE user_id = $1
+		ORDER BY id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanExchangeAlerts(rows)
+}
+
+// ExchangeRulesFor возвращает включённые правила для пары
+func (r *AlertRepository) ExchangeRulesFor(ctx context.Context, base, target string) ([]models.ExchangeAlert, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+exchangeAlertColumns+`
+		FROM exchange_alerts
+		WHERE base = $1 AND target = $2 AND enabled
+	`, base, target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanExchangeAlerts(rows)
+}
+
+func (r *AlertRepository) DeleteExchange(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM exchange_alerts WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+// ObserveExchange запоминает последний курс, на котором правило проверялось
+func (r *AlertRepository) ObserveExchange(ctx context.Context, id int64, rate float64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE exchange_alerts SET last_rate = $2 WHERE id = $1`, id, rate)
+	return err
+}
+
+// TriggerExchange отмечает срабатывание правила, если не идёт cooldown.
+// Проверка и обновление — один UPDATE, поэтому две реплики воркера
+// не отправят одно и то же уведомление дважды.
+func (r *AlertRepository) TriggerExchange(ctx context.Context, id int64, rate float64, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE exchange_alerts
+		SET last_rate = $2, last_triggered_at = $3
+		WHERE id = $1
+		  AND (last_triggered_at IS NULL
+		       OR last_triggered_at <= $3 - make_interval(secs => cooldown_seconds))
+	`, id, rate, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *AlertRepository) CreateWeather(ctx context.Context, alert models.WeatherAlert) (*models.WeatherAlert, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO weather_alerts (user_id, city, metric, operator, threshold, match, period_seconds, enabled)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)
+		RETURNING id, created_at
+	`, alert.UserID, alert.City, alert.Metric, alert.Operator, alert.Threshold, alert.Match, alert.PeriodSeconds, alert.Enabled,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *AlertRepository) ListWeather(ctx context.Context, userID int64) ([]models.WeatherAlert, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+weatherAlertColumns+`
+		FROM weather_alerts
+		WHERE user_id = $1
+		ORDER BY id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWeatherAlerts(rows)
+}
+
+// WeatherRulesFor возвращает включённые правила для города
+func (r *AlertRepository) WeatherRulesFor(ctx context.Context, city string) ([]models.WeatherAlert, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+weatherAlertColumns+`
+		FROM weather_alerts
+		WHERE city = $1 AND enabled
+	`, city)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWeatherAlerts(rows)
+}
+
+func (r *AlertRepository) DeleteWeather(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM weather_alerts WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+// TriggerWeather отмечает срабатывание, если в текущем периоде правило ещё
+// не срабатывало. Периоды выровнены по эпохе: при period_seconds = 86400
+// это «не чаще раза в сутки по UTC».
+func (r *AlertRepository) TriggerWeather(ctx context.Context, id int64, now time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE weather_alerts
+		SET last_triggered_at = $2
+		WHERE id = $1
+		  AND (last_triggered_at IS NULL
+		       OR FLOOR(EXTRACT(EPOCH FROM last_triggered_at) / period_seconds)
+		          < FLOOR(EXTRACT(EPOCH FROM $2::timestamptz) / period_seconds))
+	`, id, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *AlertRepository) SaveEvent(ctx context.Context, event models.AlertEvent) (*models.AlertEvent, error) {
+	payload := event.Payload
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO alert_events (rule_id, user_id, kind, message, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, event.RuleID, event.UserID, event.Kind, event.Message, string(payload)).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ListEvents — последние сработавшие правила пользователя, новые первыми
+func (r *AlertRepository) ListEvents(ctx context.Context, userID int64, limit int) ([]models.AlertEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, rule_id, user_id, kind, message, payload, created_at
+		FROM alert_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.AlertEvent, 0)
+	for rows.Next() {
+		var e models.AlertEvent
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.RuleID, &e.UserID, &e.Kind, &e.Message, &payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.Payload = payload
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+const exchangeAlertColumns = `id, user_id, base, target, direction, threshold,
+		cooldown_seconds, enabled, last_rate, last_triggered_at, created_at`
+
+func scanExchangeAlerts(rows *sql.Rows) ([]models.ExchangeAlert, error) {
+	alerts := make([]models.ExchangeAlert, 0)
+	for rows.Next() {
+		var a models.ExchangeAlert
+		var lastRate sql.NullFloat64
+		var lastTriggered sql.NullTime
+		err := rows.Scan(
+			&a.ID, &a.UserID, &a.Base, &a.Target, &a.Direction, &a.Threshold,
+			&a.CooldownSeconds, &a.Enabled, &lastRate, &lastTriggered, &a.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if lastRate.Valid {
+			a.LastRate = &lastRate.Float64
+		}
+		if lastTriggered.Valid {
+			a.LastTriggeredAt = &lastTriggered.Time
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+const weatherAlertColumns = `id, user_id, city, metric, operator, threshold,
+		COALESCE(match, ''), period_seconds, enabled, last_triggered_at, created_at`
+
+func scanWeatherAlerts(rows *sql.Rows) ([]models.WeatherAlert, error) {
+	alerts := make([]models.WeatherAlert, 0)
+	for rows.Next() {
+		var a models.WeatherAlert
+		var threshold sql.NullFloat64
+		var lastTriggered sql.NullTime
+		err := rows.Scan(
+			&a.ID, &a.UserID, &a.City, &a.Metric, &a.Operator, &threshold,
+			&a.Match, &a.PeriodSeconds, &a.Enabled, &lastTriggered, &a.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if threshold.Valid {
+			a.Threshold = &threshold.Float64
+		}
+		if lastTriggered.Valid {
+			a.LastTriggeredAt = &lastTriggered.Time
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"service-info/internal/models"
+)
+
+var ErrDigestNotFound = errors.New("digest schedule not found")
+
+type DigestRepository struct {
+	db *sql.DB
+}
+
+func NewDigestRepository(db *sql.DB) *DigestRepository {
+	return &DigestRepository{db: db}
+}
+
+const digestColumns = `user_id, to_char(time_of_day, 'HH24:MI'), timezone, enabled,
+		COALESCE(to_char(last_sent_date, 'YYYY-MM-DD'), ''), updated_at`
+
+func (r *DigestRepository) Get(ctx context.Context, userID int64) (*models.DigestSchedule, error) {
+	var d models.DigestSchedule
+	err := r.db.QueryRowContext(ctx, `
+		SELECT `+digestColumns+`
+		FROM digest_schedules
+		WHERE user_id = $1
+	`, userID).Scan(&d.UserID, &d.TimeOfDay, &d.Timezone, &d.Enabled, &d.LastSentDate, &d.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDigestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Save создаёт или меняет расписание; дата последней отправки сохраняется,
+// чтобы смена времени не приводила ко второй сводке за тот же день
+func (r *DigestRepository) Save(ctx context.Context, d models.DigestSchedule) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO digest_schedules (user_id, time_of_day, timezone, enabled, updated_at)
+		VALUES ($1, $2::time, $3, $4, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET time_of_day = EXCLUDED.time_of_day,
+		    timezone = EXCLUDED.timezone,
+		    enabled = EXCLUDED.enabled,
+		    updated_at = NOW()
+	`, d.UserID, d.TimeOfDay, d.Timezone, d.Enabled)
+	return err
+}
+
+func (r *DigestRepository) Delete(ctx context.Context, userID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM digest_schedules WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrDigestNotFound
+	}
+	return nil
+}
+
+// Due возвращает расписания, у которых в часовом поясе пользователя уже
+// наступило время сводки, а сегодня она ещё не отправлялась.
+// В LastSentDate возвращается текущая локальная дата пользователя — её
+// нужно передать в Claim.
+func (r *DigestRepository) Due(ctx context.Context, now time.Time, limit int) ([]models.DigestSchedule, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, to_char(time_of_day, 'HH24:MI'), timezone, enabled,
+		       to_char(($1::timestamptz AT TIME ZONE timezone)::date, 'YYYY-MM-DD'), updated_at
+		FROM digest_schedules
+		WHERE enabled
+		  AND ($1::timestamptz AT TIME ZONE timezone)::time >= time_of_day
+		  AND (last_sent_date IS NULL OR last_sent_date < ($1::timestamptz AT TIME ZONE timezone)::date)
+		ORDER BY user_id
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := make([]models.DigestSchedule, 0)
+	for rows.Next() {
+		var d models.DigestSchedule
+		if err := rows.Scan(&d.UserID, &d.TimeOfDay, &d.Timezone, &d.Enabled, &d.LastSentDate, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, d)
+	}
+	return schedules, rows.Err()
+}
+
+// Claim атомарно отмечает сводку за localDate отправленной. false — её уже
+// забрал другой запуск (или другая реплика), отправлять повторно не нужно.
+// Бывший лидер со сменившимся сроком term получает ErrStaleTerm.
+func (r *DigestRepository) Claim(ctx context.Context, term int64, userID int64, localDate string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := fence(ctx, tx, term); err != nil {
+		return false, err
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE digest_schedules
+		SET last_sent_date = $2::date
+		WHERE user_id = $1 AND (last_sent_date IS NULL OR last_sent_date < $2::date)
+	`, userID, localDate)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, tx.Commit()
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrStaleTerm — запись от реплики, чей срок лидерства уже сменился
+var ErrStaleTerm = errors.New("stale leader term")
+
+// fence — fencing записей cron-лидера. В транзакции записи запоминает term
+// как последний увиденный срок и отказывает, если в БД уже есть более
+// новый. Строка leader_terms заблокирована до конца транзакции, так что
+// запись бывшего лидера не проскочит после записи нового. term 0 — запуск
+// вне выборов лидера (тесты, ручной запуск), проверка не нужна.
+func fence(ctx context.Context, tx *sql.Tx, term int64) error {
+	if term == 0 {
+		return nil
+	}
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO leader_terms (name, term) VALUES ('cron', $1)
+		ON CONFLICT (name) DO UPDATE
+		SET term = EXCLUDED.term, updated_at = NOW()
+		WHERE leader_terms.term <= EXCLUDED.term
+	`, term)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrStaleTerm
+	}
+	return nil
+}
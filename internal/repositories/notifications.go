@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+
+	"service-info/internal/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrChannelNotFound = errors.New("notification channel not found")
+	ErrChannelExists   = errors.New("notification channel already exists")
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateChannel сохраняет канал. Webhook-каналу выдаётся собственный
+// секрет подписи — он возвращается в ch.Secret.
+func (r *NotificationRepository) CreateChannel(ctx context.Context, ch models.NotificationChannel) (*models.NotificationChannel, error) {
+	ch.Secret = ""
+	if ch.Channel == models.ChannelWebhook {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		ch.Secret = hex.EncodeToString(secret)
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO notification_channels (user_id, channel, destination, secret, enabled)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id, created_at
+	`, ch.UserID, ch.Channel, ch.Destination, ch.Secret, ch.Enabled).Scan(&ch.ID, &ch.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, ErrChannelExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+// ListChannels возвращает каналы пользователя вместе с секретами;
+// onlyEnabled — только включённые
+func (r *NotificationRepository) ListChannels(ctx context.Context, userID int64, onlyEnabled bool) ([]models.NotificationChannel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, channel, destination, COALESCE(secret, ''), enabled, created_at
+		FROM notification_channels
+		WHERE user_id = $1 AND (enabled OR NOT $2)
+		ORDER BY id
+	`, userID, onlyEnabled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := make([]models.NotificationChannel, 0)
+	for rows.Next() {
+		var ch models.NotificationChannel
+		if err := rows.Scan(&ch.ID, &ch.UserID, &ch.Channel, &ch.Destination, &ch.Secret, &ch.Enabled, &ch.CreatedAt); err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+	return channels, rows.Err()
+}
+
+func (r *NotificationRepository) DeleteChannel(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM notification_channels WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
+
+// Delivered сообщает, было ли уведомление уже доставлено в канал
+func (r *NotificationRepository) Delivered(ctx context.Context, notificationID string, channelID int64) (bool, error) {
+	var delivered bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM notification_deliveries
+			WHERE notification_id = $1 AND channel_id = $2 AND status = $3
+		)
+	`, notificationID, channelID, models.DeliveryDelivered).Scan(&delivered)
+	return delivered, err
+}
+
+// LogDelivery записывает результат доставки. Повторная доставка того же
+// уведомления (например, после перезапуска консьюмера) обновляет запись
+// и накапливает число попыток.
+func (r *NotificationRepository) LogDelivery(ctx context.Context, d models.NotificationDelivery) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notification_deliveries
+			(notification_id, user_id, channel_id, channel, status, attempts, last_error, delivered_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+		ON CONFLICT (notification_id, channel_id) DO UPDATE
+		SET status = EXCLUDED.status,
+		    attempts = notification_deliveries.attempts + EXCLUDED.attempts,
+		    last_error = EXCLUDED.last_error,
+		    delivered_at = EXCLUDED.delivered_at
+	`, d.NotificationID, d.UserID, d.ChannelID, d.Channel, d.Status, d.Attempts, d.LastError, d.DeliveredAt)
+	return err
+}
+
+// ListDeliveries — журнал доставки пользователя, новые записи первыми
+func (r *NotificationRepository) ListDeliveries(ctx context.Context, userID int64, limit int) ([]models.NotificationDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, notification_id, user_id, channel_id, channel, status, attempts,
+		       COALESCE(last_error, ''), created_at, delivered_at
+		FROM notification_deliveries
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]models.NotificationDelivery, 0)
+	for rows.Next() {
+		var d models.NotificationDelivery
+		var deliveredAt sql.NullTime
+		err := rows.Scan(&d.ID, &d.NotificationID, &d.UserID, &d.ChannelID, &d.Channel, &d.Status,
+			&d.Attempts, &d.LastError, &d.CreatedAt, &deliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		if deliveredAt.Valid {
+			d.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
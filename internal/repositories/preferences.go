@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"service-info/internal/models"
+)
+
+type PreferencesRepository struct {
+	db *sql.DB
+}
+
+func NewPreferencesRepository(db *sql.DB) *PreferencesRepository {
+	return &PreferencesRepository{db: db}
+}
+
+// Get возвращает настройки пользователя; если их нет — пустые настройки
+func (r *PreferencesRepository) Get(ctx context.Context, userID int64) (*models.UserPreferences, error) {
+	prefs := &models.UserPreferences{UserID: userID}
+	var defaultCity sql.NullString
+	var cities, pairs []byte
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT default_city, favorite_cities, favorite_pairs, updated_at
+		FROM user_preferences
+		WHERE user_id = $1
+	`, userID).Scan(&defaultCity, &cities, &pairs, &prefs.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		prefs.FavoriteCities = []string{}
+		prefs.FavoritePairs = []models.CurrencyPair{}
+		return prefs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefs.DefaultCity = defaultCity.String
+	if err := json.Unmarshal(cities, &prefs.FavoriteCities); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(pairs, &prefs.FavoritePairs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (r *PreferencesRepository) Save(ctx context.Context, prefs models.UserPreferences) error {
+	cities, err := json.Marshal(prefs.FavoriteCities)
+	if err != nil {
+		return err
+	}
+	pairs, err := json.Marshal(prefs.FavoritePairs)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO user_preferences (user_id, default_city, favorite_cities, favorite_pairs, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET default_city = EXCLUDED.default_city,
+		    favorite_cities = EXCLUDED.favorite_cities,
+		    favorite_pairs = EXCLUDED.favorite_pairs,
+		    updated_at = EXCLUDED.updated_at
+	`, prefs.UserID, prefs.DefaultCity, string(cities), string(pairs), prefs.UpdatedAt)
+	return err
+}
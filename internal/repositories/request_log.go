@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"service-info/internal/models"
+)
+
+type RequestLogRepository struct {
+	db *sql.DB
+}
+
+func NewRequestLogRepository(db *sql.DB) *RequestLogRepository {
+	return &RequestLogRepository{db: db}
+}
+
+func (r *RequestLogRepository) Save(ctx context.Context, event models.RequestEvent) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO request_log (type, args, user_id, created_at)
+		VALUES ($1, $2, NULLIF($3, 0), $4)
+	`, event.Type, event.Args, event.UserID, event.CreatedAt)
+	return err
+}
@@ -0,0 +1,39 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrInvalid = errors.New("invalid schedule")
+
+// parser понимает стандартные 5-польные cron-выражения
+// и дескрипторы вида @hourly, @daily, @every 10m
+var parser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+func Parse(expr string) (cron.Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalid)
+	}
+	s, err := parser.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return s, nil
+}
+
+// Next возвращает ближайший момент запуска после from
+func Next(expr string, from time.Time) (time.Time, error) {
+	s, err := Parse(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.Next(from), nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+)
+
+const (
+	defaultAlertCooldown = time.Hour
+	maxAlertsPerUser     = 50
+)
+
+var ErrInvalidAlert = errors.New("invalid alert")
+
+// AlertService хранит правила оповещений и проверяет их на каждом новом
+// значении, которое воркер кладёт в кэш. Сработавшие правила сохраняются
+// в alert_events и публикуются в топик notifications.
+type AlertService struct {
+	repo     *repositories.AlertRepository
+	producer kafka.ProducerInterface
+}
+
+func NewAlertService(repo *repositories.AlertRepository, producer kafka.ProducerInterface) *AlertService {
+	return &AlertService{repo: repo, producer: producer}
+}
+
+func (s *AlertService) CreateExchangeAlert(ctx context.Context, alert models.ExchangeAlert) (*models.ExchangeAlert, error) {
+	args, err := ExchangeCommand.Parse([]string{alert.Base, alert.Target})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlert, err)
+	}
+	alert.Base, alert.Target = args["base"], args["target"]
+
+	alert.Direction = strings.ToLower(strings.TrimSpace(alert.Direction))
+	if alert.Direction != models.AlertAbove && alert.Direction != models.AlertBelow {
+		return nil, fmt.Errorf("%w: direction must be %q or %q", ErrInvalidAlert, models.AlertAbove, models.AlertBelow)
+	}
+	if alert.Threshold <= 0 {
+		return nil, fmt.Errorf("%w: threshold must be positive", ErrInvalidAlert)
+	}
+	if alert.CooldownSeconds < 0 {
+		return nil, fmt.Errorf("%w: cooldown_seconds must not be negative", ErrInvalidAlert)
+	}
+	if alert.CooldownSeconds == 0 {
+		alert.CooldownSeconds = int(defaultAlertCooldown.Seconds())
+	}
+	alert.Enabled = true
+
+	existing, err := s.repo.ListExchange(ctx, alert.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAlertsPerUser {
+		return nil, fmt.Errorf("%w: at most %d alerts per user", ErrInvalidAlert, maxAlertsPerUser)
+	}
+
+	return s.repo.CreateExchange(ctx, alert)
+}
+
+func (s *AlertService) ListExchangeAlerts(ctx context.Context, userID int64) ([]models.ExchangeAlert, error) {
+	return s.repo.ListExchange(ctx, userID)
+}
+
+func (s *AlertService) DeleteExchangeAlert(ctx context.Context, userID, id int64) error {
+	return s.repo.DeleteExchange(ctx, userID, id)
+}
+
+func (s *AlertService) ListEvents(ctx context.Context, userID int64, limit int) ([]models.AlertEvent, error) {
+	return s.repo.ListEvents(ctx, userID, limit)
+}
+
+// EvaluateExchange проверяет правила пары на новом курсе. Правило срабатывает,
+// когда курс пересекает порог: сейчас условие выполнено, а на предыдущем
+// курсе — нет. Ошибки только логируются: кэширование курса важнее оповещений.
+func (s *AlertService) EvaluateExchange(ctx context.Context, rate *models.ExchangeRate) {
+	if s == nil || rate == nil {
+		return
+	}
+
+	base, target := strings.ToUpper(rate.Base), strings.ToUpper(rate.Target)
+	rules, err := s.repo.ExchangeRulesFor(ctx, base, target)
+	if err != nil {
+		log.Printf("Exchange alerts for %s/%s unavailable: %v", base, target, err)
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		if !crossed(rule, rate.Rate) {
+			if err := s.repo.ObserveExchange(ctx, rule.ID, rate.Rate); err != nil {
+				log.Printf("Exchange alert %d observe failed: %v", rule.ID, err)
+			}
+			continue
+		}
+
+		fired, err := s.repo.TriggerExchange(ctx, rule.ID, rate.Rate, now)
+		if err != nil {
+			log.Printf("Exchange alert %d trigger failed: %v", rule.ID, err)
+			continue
+		}
+		if !fired {
+			// Cooldown: уровень запоминаем, чтобы следующее пересечение было честным
+			if err := s.repo.ObserveExchange(ctx, rule.ID, rate.Rate); err != nil {
+				log.Printf("Exchange alert %d observe failed: %v", rule.ID, err)
+			}
+			continue
+		}
+
+		s.emitExchange(ctx, rule, rate)
+	}
+}
+
+func crossed(rule models.ExchangeAlert, rate float64) bool {
+	holds := func(v float64) bool {
+		if rule.Direction == models.AlertAbove {
+			return v >= rule.Threshold
+		}
+		return v <= rule.Threshold
+	}
+	if !holds(rate) {
+		return false
+	}
+	return rule.LastRate == nil || !holds(*rule.LastRate)
+}
+
+func (s *AlertService) emitExchange(ctx context.Context, rule models.ExchangeAlert, rate *models.ExchangeRate) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"base":      rule.Base,
+		"target":    rule.Target,
+		"direction": rule.Direction,
+		"threshold": rule.Threshold,
+		"rate":      rate.Rate,
+	})
+	s.emit(ctx, models.AlertEvent{
+		RuleID:  rule.ID,
+		UserID:  rule.UserID,
+		Kind:    models.NotificationExchangeAlert,
+		Message: fmt.Sprintf("%s/%s is %s %g: %g", rule.Base, rule.Target, rule.Direction, rule.Threshold, rate.Rate),
+		Payload: payload,
+	})
+}
+
+// emit сохраняет событие и публикует уведомление
+func (s *AlertService) emit(ctx context.Context, event models.AlertEvent) {
+	saved, err := s.repo.SaveEvent(ctx, event)
+	if err != nil {
+		log.Printf("%s %d event save failed: %v", event.Kind, event.RuleID, err)
+		return
+	}
+
+	log.Printf("🔔 %s %d fired for user %d: %s", saved.Kind, saved.RuleID, saved.UserID, saved.Message)
+	s.notify(saved)
+}
+
+func (s *AlertService) notify(event *models.AlertEvent) {
+	if s.producer == nil {
+		return
+	}
+	s.producer.PublishObjectAsync([]byte(fmt.Sprint(event.UserID)), models.Notification{
+		ID:        fmt.Sprintf("alert:%d", event.ID),
+		EventID:   event.ID,
+		UserID:    event.UserID,
+		Kind:      event.Kind,
+		Message:   event.Message,
+		Payload:   event.Payload,
+		CreatedAt: event.CreatedAt,
+	})
+}
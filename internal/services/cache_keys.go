@@ -0,0 +1,44 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CacheKeys строит ключи кэша из городов и пар вида usd_eur, без повторов.
+// Нужен подпискам на обновления (SSE, WebSocket, gRPC), которые адресуют
+// значения напрямую ключами Redis.
+func CacheKeys(cities, pairs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	for _, city := range cities {
+		if city = strings.TrimSpace(city); city != "" {
+			add(WeatherFetcher{}.CacheKey(city))
+		}
+	}
+	for _, pair := range pairs {
+		base, target, ok := strings.Cut(strings.TrimSpace(pair), "_")
+		if !ok || base == "" || target == "" {
+			return nil, fmt.Errorf("exchange must look like usd_eur, got %q", pair)
+		}
+		add(ExchangeFetcher{}.CacheKey(base, target))
+	}
+	return keys, nil
+}
+
+// StaleTTL — сколько хранится последняя известная копия значения.
+// Её отдают, когда свежего значения в кэше нет, а провайдер недоступен.
+const StaleTTL = 24 * time.Hour
+
+// StaleKey — ключ последней известной копии значения из ключа кэша
+func StaleKey(key string) string {
+	return "stale:" + key
+}
@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"service-info/internal/api"
 	"service-info/internal/kafka"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -27,8 +30,9 @@ func NewCacheService[T any](
 	}
 }
 
-func (s *CacheService[T]) Get(params ...string) (*T, error) {
-	ctx := context.Background()
+// Get читает значение из кэша, при промахе запрашивает его у fetcher.
+// ctx запроса доходит до провайдера: отключение клиента отменяет вызов.
+func (s *CacheService[T]) Get(ctx context.Context, params ...string) (*T, error) {
 	key := s.fetcher.CacheKey(params...)
 
 	if data, err := s.redis.Get(ctx, key).Bytes(); err == nil {
@@ -39,8 +43,11 @@ func (s *CacheService[T]) Get(params ...string) (*T, error) {
 		}
 	}
 
-	result, err := s.fetcher.Fetch(params...)
+	result, err := s.fetcher.Fetch(ctx, params...)
 	if err != nil {
+		if stale, ok := s.stale(ctx, key, err); ok {
+			return stale, nil
+		}
 		return nil, err
 	}
 
@@ -50,3 +57,88 @@ func (s *CacheService[T]) Get(params ...string) (*T, error) {
 
 	return result, nil
 }
+
+// GetMany читает значения одним MGET, промахи запрашивает у fetcher
+// параллельно — не больше workers одновременно. Результаты и ошибки
+// выровнены по params: сбой одного элемента не мешает остальным.
+func (s *CacheService[T]) GetMany(ctx context.Context, params [][]string, workers int) ([]*T, []error) {
+	results := make([]*T, len(params))
+	errs := make([]error, len(params))
+	if len(params) == 0 {
+		return results, errs
+	}
+
+	keys := make([]string, len(params))
+	for i, p := range params {
+		keys[i] = s.fetcher.CacheKey(p...)
+	}
+
+	values, err := s.redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		log.Printf("Redis MGET error: %v", err)
+		values = make([]any, len(keys))
+	}
+
+	var misses []int
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			var result T
+			if json.Unmarshal([]byte(str), &result) == nil {
+				results[i] = &result
+				continue
+			}
+		}
+		misses = append(misses, i)
+	}
+	log.Printf("Cache MGET: %d hits, %d misses", len(params)-len(misses), len(misses))
+
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, i := range misses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.fetcher.Fetch(ctx, params[i]...)
+			if err != nil {
+				if stale, ok := s.stale(ctx, keys[i], err); ok {
+					results[i] = stale
+					return
+				}
+				errs[i] = err
+				return
+			}
+			results[i] = result
+			if s.producer != nil {
+				s.producer.PublishObjectAsync([]byte(keys[i]), result)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+// stale отдаёт последнюю известную копию значения, если провайдер
+// недоступен (в том числе когда разомкнут его выключатель). На остальные
+// ошибки — неизвестный город, исчерпанная квота — старые данные не отвечают.
+func (s *CacheService[T]) stale(ctx context.Context, key string, err error) (*T, bool) {
+	if !errors.Is(err, api.ErrUpstreamUnavailable) {
+		return nil, false
+	}
+	data, redisErr := s.redis.Get(ctx, StaleKey(key)).Bytes()
+	if redisErr != nil {
+		return nil, false
+	}
+	var result T
+	if json.Unmarshal(data, &result) != nil {
+		return nil, false
+	}
+	log.Printf("Cache STALE: %s (%v)", key, err)
+	return &result, true
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // в alpine-образе нет системной базы часовых поясов
+
+	"service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+)
+
+var ErrInvalidDigest = errors.New("invalid digest schedule")
+
+// DigestService хранит расписания утренних сводок и собирает сводку из
+// избранного пользователя через те же CacheService, что и /me/dashboard.
+// Готовая сводка уходит в топик notifications и доставляется его каналами.
+type DigestService struct {
+	repo     *repositories.DigestRepository
+	prefs    *PreferencesService
+	producer kafka.ProducerInterface
+}
+
+func NewDigestService(repo *repositories.DigestRepository, prefs *PreferencesService, producer kafka.ProducerInterface) *DigestService {
+	return &DigestService{repo: repo, prefs: prefs, producer: producer}
+}
+
+func (s *DigestService) Get(ctx context.Context, userID int64) (*models.DigestSchedule, error) {
+	return s.repo.Get(ctx, userID)
+}
+
+func (s *DigestService) Save(ctx context.Context, d models.DigestSchedule) (*models.DigestSchedule, error) {
+	d.TimeOfDay = strings.TrimSpace(d.TimeOfDay)
+	if _, err := time.Parse("15:04", d.TimeOfDay); err != nil {
+		return nil, fmt.Errorf("%w: time_of_day must look like 08:00", ErrInvalidDigest)
+	}
+	d.Timezone = strings.TrimSpace(d.Timezone)
+	if d.Timezone == "" {
+		d.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(d.Timezone); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidDigest, d.Timezone)
+	}
+
+	if err := s.repo.Save(ctx, d); err != nil {
+		return nil, err
+	}
+	return s.repo.Get(ctx, d.UserID)
+}
+
+func (s *DigestService) Delete(ctx context.Context, userID int64) error {
+	return s.repo.Delete(ctx, userID)
+}
+
+// Due — расписания, по которым пора отправить сводку
+func (s *DigestService) Due(ctx context.Context, now time.Time, limit int) ([]models.DigestSchedule, error) {
+	return s.repo.Due(ctx, now, limit)
+}
+
+// Send отправляет сводку за локальную дату из d.LastSentDate (её заполняет Due).
+// Сначала сводка собирается целиком, затем дата отмечается в базе и только
+// потом публикуется уведомление: ошибка сборки не сжигает день, а после
+// перезапуска сводка за этот день уже не уйдёт второй раз. term — срок
+// лидерства cron-реплики (0 вне выборов лидера).
+func (s *DigestService) Send(ctx context.Context, term int64, d models.DigestSchedule) (bool, error) {
+	dashboard, err := s.prefs.Dashboard(ctx, d.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	payload, err := json.Marshal(dashboard)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := s.repo.Claim(ctx, term, d.UserID, d.LastSentDate)
+	if err != nil || !claimed {
+		return false, err
+	}
+	// Пустое избранное: день отмечен, отправлять нечего
+	if len(dashboard.Weather) == 0 && len(dashboard.Exchange) == 0 {
+		return false, nil
+	}
+
+	s.producer.PublishObjectAsync([]byte(fmt.Sprint(d.UserID)), models.Notification{
+		ID:        fmt.Sprintf("digest:%d:%s", d.UserID, d.LastSentDate),
+		UserID:    d.UserID,
+		Kind:      models.NotificationDigest,
+		Message:   DigestText(d.LastSentDate, dashboard),
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	})
+	return true, nil
+}
+
+// DigestText — текст сводки: по строке на город и валютную пару
+func DigestText(date string, dashboard *models.Dashboard) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Сводка на %s\n", date)
+
+	for _, city := range sortedKeys(dashboard.Weather) {
+		item := dashboard.Weather[city]
+		if item.Data == nil {
+			fmt.Fprintf(&b, "🌡 %s: %s\n", city, item.Error)
+			continue
+		}
+		w := item.Data
+		fmt.Fprintf(&b, "🌡 %s: %.0f°C (ощущается как %.0f°C), %s, ветер %.0f км/ч\n",
+			w.City, w.Temp, w.FeelsLike, w.Condition, w.WindKPH)
+	}
+
+	for _, pair := range sortedKeys(dashboard.Exchange) {
+		item := dashboard.Exchange[pair]
+		name := strings.Replace(pair, "_", "/", 1)
+		if item.Data == nil {
+			fmt.Fprintf(&b, "💱 %s: %s\n", name, item.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "💱 %s: %.4f\n", name, item.Data.Rate)
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
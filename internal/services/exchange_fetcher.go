@@ -1,13 +1,34 @@
 package services
 
 import (
+	"context"
 	"strings"
 
 	"service-info/internal/api"
+	"service-info/internal/breaker"
+	"service-info/internal/commands"
 	"service-info/internal/models"
+	"service-info/internal/quota"
 )
 
-type ExchangeFetcher struct{}
+// ExchangeCommand — команда админки для задач курсов валют
+var ExchangeCommand = commands.Command{
+	Name:        "/exchange",
+	TaskType:    "exchange",
+	Description: "exchange rate between two currencies",
+	Args: []commands.ArgSpec{
+		{Name: "base", Required: true, Key: true, Help: "ISO 4217 code of the base currency, e.g. USD", Normalize: commands.Upper, Validate: commands.Letters(3)},
+		{Name: "target", Required: true, Key: true, Help: "ISO 4217 code of the target currency, e.g. EUR", Normalize: commands.Upper, Validate: commands.Letters(3)},
+	},
+	CacheKey: func(args models.TaskArgs) string {
+		return ExchangeFetcher{}.CacheKey(args["base"], args["target"])
+	},
+}
+
+type ExchangeFetcher struct {
+	Quota    *quota.Tracker
+	Breakers *breaker.Set
+}
 
 func (ExchangeFetcher) CacheKey(params ...string) string {
 	base := strings.ToLower(params[0])
@@ -15,6 +36,16 @@ func (ExchangeFetcher) CacheKey(params ...string) string {
 	return "exchange:" + base + "_" + target
 }
 
-func (ExchangeFetcher) Fetch(params ...string) (*models.ExchangeRate, error) {
-	return api.FetchExchangeRate(params[0], params[1])
+func (f ExchangeFetcher) Fetch(ctx context.Context, params ...string) (*models.ExchangeRate, error) {
+	// Отказ по квоте — не ответ провайдера и не должен попадать в выключатель
+	if err := f.Quota.Acquire(ctx, quota.ProviderFreeCurrency, quota.PriorityEssential); err != nil {
+		return nil, &api.Error{Provider: quota.ProviderFreeCurrency, Kind: api.ErrQuotaExceeded, Err: err}
+	}
+	var rate *models.ExchangeRate
+	err := CallUpstream(ctx, f.Breakers, quota.ProviderFreeCurrency, func() error {
+		var err error
+		rate, err = api.FetchExchangeRate(ctx, params[0], params[1])
+		return err
+	})
+	return rate, err
 }
@@ -1,6 +1,11 @@
 package services
 
+import "context"
+
 type Fetcher[T any] interface {
 	CacheKey(params ...string) string
-	Fetch(params ...string) (*T, error)
+	// Fetch возвращает *api.Error: вид ошибки (api.ErrNotFound и т.п.)
+	// проверяется через errors.Is и доходит до обработчиков как есть.
+	// Отмена ctx прерывает запрос к провайдеру.
+	Fetch(ctx context.Context, params ...string) (*T, error)
 }
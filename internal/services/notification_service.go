@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"service-info/internal/models"
+	"service-info/internal/notify"
+	"service-info/internal/repositories"
+)
+
+const maxChannelsPerUser = 10
+
+var ErrInvalidChannel = errors.New("invalid notification channel")
+
+// NotificationService управляет каналами доставки пользователя и журналом доставок
+type NotificationService struct {
+	repo       *repositories.NotificationRepository
+	dispatcher *notify.Dispatcher
+}
+
+func NewNotificationService(repo *repositories.NotificationRepository, dispatcher *notify.Dispatcher) *NotificationService {
+	return &NotificationService{repo: repo, dispatcher: dispatcher}
+}
+
+func (s *NotificationService) AddChannel(ctx context.Context, ch models.NotificationChannel) (*models.NotificationChannel, error) {
+	ch.Channel = strings.ToLower(strings.TrimSpace(ch.Channel))
+	ch.Destination = strings.TrimSpace(ch.Destination)
+	if err := s.dispatcher.Validate(ch.Channel, ch.Destination); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChannel, err)
+	}
+	ch.Enabled = true
+
+	existing, err := s.repo.ListChannels(ctx, ch.UserID, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxChannelsPerUser {
+		return nil, fmt.Errorf("%w: at most %d channels per user", ErrInvalidChannel, maxChannelsPerUser)
+	}
+
+	return s.repo.CreateChannel(ctx, ch)
+}
+
+// ListChannels — каналы пользователя без секретов: секрет webhook
+// показывается только в ответе на создание канала
+func (s *NotificationService) ListChannels(ctx context.Context, userID int64) ([]models.NotificationChannel, error) {
+	channels, err := s.repo.ListChannels(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	for i := range channels {
+		channels[i].Secret = ""
+	}
+	return channels, nil
+}
+
+func (s *NotificationService) DeleteChannel(ctx context.Context, userID, id int64) error {
+	return s.repo.DeleteChannel(ctx, userID, id)
+}
+
+func (s *NotificationService) ListDeliveries(ctx context.Context, userID int64, limit int) ([]models.NotificationDelivery, error) {
+	return s.repo.ListDeliveries(ctx, userID, limit)
+}
+
+// AvailableChannels — каналы, настроенные на сервере
+func (s *NotificationService) AvailableChannels() []string {
+	return s.dispatcher.Channels()
+}
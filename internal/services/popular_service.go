@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"service-info/internal/commands"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const DefaultPopularWindow = "24h"
+
+// PopularWindows — окна публичных рейтингов /popular; они переопределяют
+// окно из политики типа, остальные параметры политики сохраняются
+var PopularWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+}
+
+var (
+	ErrUnknownWindow = errors.New("unknown popularity window")
+	ErrUnknownType   = errors.New("unknown popularity type")
+)
+
+// PopularService считает рейтинги популярных запросов для всех
+// зарегистрированных типов, хранит их в Redis и отдаёт вместе с текущими
+// значениями из кэша
+type PopularService struct {
+	redis     *redis.Client
+	adminRepo *repositories.AdminRepository
+	commands  *commands.Registry
+	policy    func(taskType string) models.PopularityPolicy
+	ttl       time.Duration
+}
+
+func NewPopularService(
+	redisClient *redis.Client,
+	adminRepo *repositories.AdminRepository,
+	registry *commands.Registry,
+	policy func(taskType string) models.PopularityPolicy,
+	ttl time.Duration,
+) *PopularService {
+	return &PopularService{
+		redis:     redisClient,
+		adminRepo: adminRepo,
+		commands:  registry,
+		policy:    policy,
+		ttl:       ttl,
+	}
+}
+
+// Rank возвращает топ по всем типам задач; window > 0 переопределяет окно политики
+func (s *PopularService) Rank(ctx context.Context, window time.Duration) ([]models.PopularRequest, error) {
+	var result []models.PopularRequest
+	for _, cmd := range s.commands.Commands() {
+		policy := s.policy(cmd.TaskType)
+		if window > 0 {
+			policy.Window = window
+		}
+		top, err := s.adminRepo.GetTopRequests(ctx, cmd.TaskType, cmd.KeyArgs(), policy)
+		if err != nil {
+			return nil, fmt.Errorf("rank %s: %w", cmd.TaskType, err)
+		}
+		result = append(result, top...)
+	}
+	return result, nil
+}
+
+// RefreshSnapshots пересчитывает рейтинги всех окон для публичного /popular
+func (s *PopularService) RefreshSnapshots(ctx context.Context) {
+	now := time.Now()
+	for name, window := range PopularWindows {
+		items, err := s.Rank(ctx, window)
+		if err != nil {
+			log.Printf("Popular snapshot %s failed: %v", name, err)
+			continue
+		}
+
+		snapshot := models.PopularSnapshot{Window: name, GeneratedAt: now, Items: items}
+		if err := s.saveSnapshot(ctx, snapshot); err != nil {
+			log.Printf("Popular snapshot %s not saved: %v", name, err)
+		}
+	}
+}
+
+func (s *PopularService) saveSnapshot(ctx context.Context, snapshot models.PopularSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return s.redis.Set(ctx, snapshotKey(snapshot.Window), data, s.ttl).Err()
+}
+
+// Get возвращает рейтинг за окно; taskType == "" — все типы
+func (s *PopularService) Get(ctx context.Context, taskType, window string) (*models.PopularSnapshot, []models.PopularItem, error) {
+	if _, ok := PopularWindows[window]; !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownWindow, window)
+	}
+	if _, ok := s.commands.ByType(taskType); taskType != "" && !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnknownType, taskType)
+	}
+
+	snapshot := &models.PopularSnapshot{Window: window}
+	data, err := s.redis.Get(ctx, snapshotKey(window)).Bytes()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, snapshot); err != nil {
+			return nil, nil, fmt.Errorf("corrupted popular snapshot %s: %w", window, err)
+		}
+	}
+
+	items := make([]models.PopularItem, 0, len(snapshot.Items))
+	ranks := make(map[string]int)
+	var keys []string
+	for _, req := range snapshot.Items {
+		if taskType != "" && req.Type != taskType {
+			continue
+		}
+		ranks[req.Type]++
+		items = append(items, models.PopularItem{
+			Rank:  ranks[req.Type],
+			Type:  req.Type,
+			Args:  req.Args,
+			Count: req.Count,
+			Score: req.Score,
+		})
+		keys = append(keys, s.CacheKey(req))
+	}
+
+	if len(keys) > 0 {
+		values, err := s.redis.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, v := range values {
+			if str, ok := v.(string); ok {
+				items[i].Value = json.RawMessage(str)
+			}
+		}
+	}
+
+	return snapshot, items, nil
+}
+
+// Types — зарегистрированные типы запросов, по которым можно фильтровать рейтинг
+func (s *PopularService) Types() []string {
+	cmds := s.commands.Commands()
+	types := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		types[i] = cmd.TaskType
+	}
+	sort.Strings(types)
+	return types
+}
+
+// CacheKey — ключ Redis, под которым воркер кэширует результат запроса
+func (s *PopularService) CacheKey(req models.PopularRequest) string {
+	cmd, ok := s.commands.ByType(req.Type)
+	if !ok || cmd.CacheKey == nil {
+		return req.Type + ":unknown"
+	}
+	return cmd.CacheKey(req.Args)
+}
+
+// TTLs возвращает оставшееся время жизни ключей кэша; для отсутствующих
+// ключей — отрицательное значение
+func (s *PopularService) TTLs(ctx context.Context, keys []string) ([]time.Duration, error) {
+	pipe := s.redis.Pipeline()
+	cmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.PTTL(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	ttls := make([]time.Duration, len(keys))
+	for i, cmd := range cmds {
+		ttls[i] = cmd.Val()
+	}
+	return ttls, nil
+}
+
+// MarkRefreshPending отмечает, что обновление ключа уже запрошено, чтобы не
+// слать повторные команды, пока воркер его не выполнил. false — отметка уже есть.
+func (s *PopularService) MarkRefreshPending(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.redis.SetNX(ctx, "refresh:pending:"+key, 1, ttl).Result()
+}
+
+func snapshotKey(window string) string {
+	return "popular:snapshot:" + window
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"service-info/internal/commands"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const maxFavorites = 10
+
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+// PreferencesService хранит настройки пользователей в Postgres
+// и кэширует их в Redis под ключом prefs:<user_id>
+type PreferencesService struct {
+	repo     *repositories.PreferencesRepository
+	redis    *redis.Client
+	weather  *CacheService[models.Weather]
+	exchange *CacheService[models.ExchangeRate]
+	ttl      time.Duration
+}
+
+func NewPreferencesService(
+	repo *repositories.PreferencesRepository,
+	redisClient *redis.Client,
+	weather *CacheService[models.Weather],
+	exchange *CacheService[models.ExchangeRate],
+	ttl time.Duration,
+) *PreferencesService {
+	return &PreferencesService{
+		repo:     repo,
+		redis:    redisClient,
+		weather:  weather,
+		exchange: exchange,
+		ttl:      ttl,
+	}
+}
+
+func (s *PreferencesService) Get(ctx context.Context, userID int64) (*models.UserPreferences, error) {
+	if s == nil {
+		return &models.UserPreferences{UserID: userID}, nil
+	}
+
+	key := prefsKey(userID)
+	if data, err := s.redis.Get(ctx, key).Bytes(); err == nil {
+		var prefs models.UserPreferences
+		if json.Unmarshal(data, &prefs) == nil {
+			prefs.UserID = userID
+			return &prefs, nil
+		}
+	}
+
+	prefs, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache(ctx, prefs)
+	return prefs, nil
+}
+
+func (s *PreferencesService) Save(ctx context.Context, prefs models.UserPreferences) (*models.UserPreferences, error) {
+	if err := normalizePreferences(&prefs); err != nil {
+		return nil, err
+	}
+	prefs.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, prefs); err != nil {
+		return nil, err
+	}
+	s.cache(ctx, &prefs)
+	return &prefs, nil
+}
+
+// DefaultCity — город для /weather без параметра: основной или первый из избранных
+func (s *PreferencesService) DefaultCity(ctx context.Context, userID int64) string {
+	prefs, err := s.Get(ctx, userID)
+	if err != nil {
+		log.Printf("Preferences of %d unavailable: %v", userID, err)
+		return ""
+	}
+	if prefs.DefaultCity != "" {
+		return prefs.DefaultCity
+	}
+	if len(prefs.FavoriteCities) > 0 {
+		return prefs.FavoriteCities[0]
+	}
+	return ""
+}
+
+// DefaultPair — валютная пара для /exchange без параметров: первая из избранных
+func (s *PreferencesService) DefaultPair(ctx context.Context, userID int64) (models.CurrencyPair, bool) {
+	prefs, err := s.Get(ctx, userID)
+	if err != nil {
+		log.Printf("Preferences of %d unavailable: %v", userID, err)
+		return models.CurrencyPair{}, false
+	}
+	if len(prefs.FavoritePairs) == 0 {
+		return models.CurrencyPair{}, false
+	}
+	return prefs.FavoritePairs[0], true
+}
+
+// Dashboard собирает погоду и курсы по всем избранным пользователя параллельно.
+// Ошибка одного элемента не мешает остальным.
+func (s *PreferencesService) Dashboard(ctx context.Context, userID int64) (*models.Dashboard, error) {
+	prefs, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cities := prefs.FavoriteCities
+	if prefs.DefaultCity != "" && !containsFold(cities, prefs.DefaultCity) {
+		cities = append([]string{prefs.DefaultCity}, cities...)
+	}
+
+	dashboard := &models.Dashboard{
+		DefaultCity: prefs.DefaultCity,
+		Weather:     make(map[string]models.DashboardItem[models.Weather], len(cities)),
+		Exchange:    make(map[string]models.DashboardItem[models.ExchangeRate], len(prefs.FavoritePairs)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, city := range cities {
+		wg.Add(1)
+		go func(city string) {
+			defer wg.Done()
+			weather, err := s.weather.Get(ctx, city)
+			item := models.DashboardItem[models.Weather]{Data: weather}
+			if err != nil {
+				log.Printf("Dashboard weather %s for %d: %v", city, userID, err)
+				item.Error = "weather unavailable"
+			}
+			mu.Lock()
+			dashboard.Weather[city] = item
+			mu.Unlock()
+		}(city)
+	}
+
+	for _, pair := range prefs.FavoritePairs {
+		wg.Add(1)
+		go func(pair models.CurrencyPair) {
+			defer wg.Done()
+			rate, err := s.exchange.Get(ctx, pair.Base, pair.Target)
+			item := models.DashboardItem[models.ExchangeRate]{Data: rate}
+			if err != nil {
+				log.Printf("Dashboard exchange %s/%s for %d: %v", pair.Base, pair.Target, userID, err)
+				item.Error = "exchange rate unavailable"
+			}
+			mu.Lock()
+			dashboard.Exchange[pair.Base+"_"+pair.Target] = item
+			mu.Unlock()
+		}(pair)
+	}
+
+	wg.Wait()
+	return dashboard, nil
+}
+
+func (s *PreferencesService) cache(ctx context.Context, prefs *models.UserPreferences) {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Set(ctx, prefsKey(prefs.UserID), data, s.ttl).Err(); err != nil {
+		log.Printf("Redis SET error %s: %v", prefsKey(prefs.UserID), err)
+	}
+}
+
+func normalizePreferences(prefs *models.UserPreferences) error {
+	prefs.DefaultCity = strings.TrimSpace(prefs.DefaultCity)
+
+	if len(prefs.FavoriteCities) > maxFavorites || len(prefs.FavoritePairs) > maxFavorites {
+		return fmt.Errorf("%w: at most %d favorite cities and %d favorite pairs", ErrInvalidPreferences, maxFavorites, maxFavorites)
+	}
+
+	cities := make([]string, 0, len(prefs.FavoriteCities))
+	for _, city := range prefs.FavoriteCities {
+		city = strings.TrimSpace(city)
+		if city == "" {
+			return fmt.Errorf("%w: empty favorite city", ErrInvalidPreferences)
+		}
+		if !containsFold(cities, city) {
+			cities = append(cities, city)
+		}
+	}
+	prefs.FavoriteCities = cities
+
+	isCode := commands.Letters(3)
+	pairs := make([]models.CurrencyPair, 0, len(prefs.FavoritePairs))
+	for _, pair := range prefs.FavoritePairs {
+		pair.Base = strings.ToUpper(strings.TrimSpace(pair.Base))
+		pair.Target = strings.ToUpper(strings.TrimSpace(pair.Target))
+		if isCode(pair.Base) != nil || isCode(pair.Target) != nil {
+			return fmt.Errorf("%w: currency pair %s/%s must use 3-letter codes", ErrInvalidPreferences, pair.Base, pair.Target)
+		}
+		// Пары сравниваются уже нормализованными: usd/eur и USD/EUR — одна пара
+		if !slices.Contains(pairs, pair) {
+			pairs = append(pairs, pair)
+		}
+	}
+	prefs.FavoritePairs = pairs
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func prefsKey(userID int64) string {
+	return "prefs:" + strconv.FormatInt(userID, 10)
+}
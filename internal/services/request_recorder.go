@@ -0,0 +1,47 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"service-info/internal/commands"
+	"service-info/internal/kafka"
+	"service-info/internal/models"
+)
+
+// RequestRecorder публикует события о запросах пользователей — из них
+// считается популярность. Аргументы нормализуются так же, как в командах
+// админки, чтобы запросы и задачи группировались вместе.
+type RequestRecorder struct {
+	producer kafka.ProducerInterface
+	commands *commands.Registry
+}
+
+func NewRequestRecorder(producer kafka.ProducerInterface, registry *commands.Registry) *RequestRecorder {
+	return &RequestRecorder{producer: producer, commands: registry}
+}
+
+func (r *RequestRecorder) Record(taskType string, userID int64, fields ...string) {
+	if r == nil || r.producer == nil {
+		return
+	}
+
+	cmd, ok := r.commands.ByType(taskType)
+	if !ok {
+		log.Printf("RequestRecorder: no command registered for %s", taskType)
+		return
+	}
+	args, err := cmd.Parse(fields)
+	if err != nil {
+		log.Printf("RequestRecorder: skip %s %s: %v", taskType, strings.Join(fields, " "), err)
+		return
+	}
+
+	r.producer.PublishObjectAsync([]byte(taskType), models.RequestEvent{
+		Type:      taskType,
+		Args:      args,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	})
+}
@@ -2,13 +2,20 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"service-info/internal/models"
 	"service-info/internal/repositories"
+	"service-info/internal/schedule"
 )
 
 type AdminServiceInterface interface {
-	SaveTask(ctx context.Context, task models.Task) error
+	SaveTask(ctx context.Context, task models.Task) (int, error)
+	UpdateSchedule(ctx context.Context, id int, expr string, enabled bool) error
+	ListTasks(ctx context.Context, filter repositories.TaskFilter) ([]models.Task, int, error)
+	GetTask(ctx context.Context, id int) (*models.Task, error)
+	DeleteTask(ctx context.Context, id int) error
+	DeleteTasks(ctx context.Context, title string, olderThan time.Duration) (int64, error)
 }
 
 type AdminService struct {
@@ -18,6 +25,49 @@ type AdminService struct {
 func NewAdminService(repo *repositories.AdminRepository) *AdminService {
 	return &AdminService{repo: repo}
 }
-func (s *AdminService) SaveTask(ctx context.Context, task models.Task) error {
+
+// SaveTask сохраняет задачу; если задано расписание, рассчитывает первый запуск
+func (s *AdminService) SaveTask(ctx context.Context, task models.Task) (int, error) {
+	if task.Schedule != "" {
+		next, err := schedule.Next(task.Schedule, time.Now())
+		if err != nil {
+			return 0, err
+		}
+		task.NextRunAt = &next
+	}
 	return s.repo.Save(ctx, task)
 }
+
+// UpdateSchedule меняет расписание задачи; пустое выражение снимает её с расписания
+func (s *AdminService) UpdateSchedule(ctx context.Context, id int, expr string, enabled bool) error {
+	var nextRun *time.Time
+	if expr != "" {
+		next, err := schedule.Next(expr, time.Now())
+		if err != nil {
+			return err
+		}
+		nextRun = &next
+	}
+	return s.repo.UpdateSchedule(ctx, id, expr, enabled, nextRun)
+}
+
+func (s *AdminService) ListTasks(ctx context.Context, filter repositories.TaskFilter) ([]models.Task, int, error) {
+	return s.repo.List(ctx, filter)
+}
+
+func (s *AdminService) GetTask(ctx context.Context, id int) (*models.Task, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *AdminService) DeleteTask(ctx context.Context, id int) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// DeleteTasks удаляет задачи по title и/или старше olderThan
+func (s *AdminService) DeleteTasks(ctx context.Context, title string, olderThan time.Duration) (int64, error) {
+	var before time.Time
+	if olderThan > 0 {
+		before = time.Now().Add(-olderThan)
+	}
+	return s.repo.DeleteMatching(ctx, title, before)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"service-info/internal/models"
+)
+
+const (
+	UnitsMetric   = "metric"
+	UnitsImperial = "imperial"
+)
+
+// ParseUnits проверяет систему единиц из запроса; пустая — метрическая
+func ParseUnits(s string) (string, error) {
+	switch units := strings.ToLower(strings.TrimSpace(s)); units {
+	case "", UnitsMetric:
+		return UnitsMetric, nil
+	case UnitsImperial:
+		return UnitsImperial, nil
+	default:
+		return "", fmt.Errorf("units must be %s or %s", UnitsMetric, UnitsImperial)
+	}
+}
+
+// WeatherInUnits возвращает погоду в нужной системе единиц. Кэш хранит
+// одно метрическое наблюдение, пересчёт делается при каждом ответе.
+func WeatherInUnits(w *models.Weather, units string) any {
+	if w == nil || units != UnitsImperial {
+		return w
+	}
+	return ToImperial(w)
+}
+
+func ToImperial(w *models.Weather) *models.WeatherImperial {
+	return &models.WeatherImperial{
+		City:            w.City,
+		Lang:            w.Lang,
+		TempF:           round(w.Temp*9/5+32, 1),
+		FeelsLike:       round(w.FeelsLike*9/5+32, 1),
+		Humidity:        w.Humidity,
+		Condition:       w.Condition,
+		WindMPH:         round(w.WindKPH/1.609344, 1),
+		PressureIn:      round(w.PressureMB*0.0295299830714, 2),
+		Cloud:           w.Cloud,
+		VisibilityMiles: round(w.VisibilityKM/1.609344, 1),
+		Updated:         w.Updated,
+	}
+}
+
+func round(v float64, digits int) float64 {
+	p := math.Pow(10, float64(digits))
+	return math.Round(v*p) / p
+}
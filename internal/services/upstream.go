@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"service-info/internal/api"
+	"service-info/internal/breaker"
+)
+
+// CallUpstream выполняет обращение к провайдеру через его выключатель.
+// Отказ разомкнутого выключателя возвращается как api.ErrUpstreamUnavailable,
+// чтобы обработчики и откат на устаревший кэш не отличали его от сбоя провайдера.
+func CallUpstream(ctx context.Context, breakers *breaker.Set, provider string, fn func() error) error {
+	err := breakers.Do(ctx, provider, fn)
+	if errors.Is(err, breaker.ErrOpen) {
+		return &api.Error{Provider: provider, Kind: api.ErrUpstreamUnavailable, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"service-info/internal/models"
+)
+
+const defaultWeatherAlertPeriod = 24 * time.Hour
+
+// weatherMetrics — числовые метрики погоды, доступные для правил
+var weatherMetrics = map[string]func(*models.Weather) float64{
+	models.WeatherMetricTemp:      func(w *models.Weather) float64 { return w.Temp },
+	models.WeatherMetricFeelsLike: func(w *models.Weather) float64 { return w.FeelsLike },
+	models.WeatherMetricWind:      func(w *models.Weather) float64 { return w.WindKPH },
+	models.WeatherMetricHumidity:  func(w *models.Weather) float64 { return float64(w.Humidity) },
+}
+
+func (s *AlertService) CreateWeatherAlert(ctx context.Context, alert models.WeatherAlert) (*models.WeatherAlert, error) {
+	args, err := WeatherCommand.Parse([]string{alert.City})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlert, err)
+	}
+	alert.City = args["city"]
+
+	alert.Metric = strings.ToLower(strings.TrimSpace(alert.Metric))
+	alert.Operator = strings.ToLower(strings.TrimSpace(alert.Operator))
+	alert.Match = strings.ToLower(strings.TrimSpace(alert.Match))
+
+	switch {
+	case alert.Metric == models.WeatherMetricCondition:
+		if alert.Operator != models.AlertContains || alert.Match == "" {
+			return nil, fmt.Errorf("%w: condition rules need operator %q and non-empty match", ErrInvalidAlert, models.AlertContains)
+		}
+		alert.Threshold = nil
+	case weatherMetrics[alert.Metric] != nil:
+		if alert.Operator != models.AlertAbove && alert.Operator != models.AlertBelow {
+			return nil, fmt.Errorf("%w: operator must be %q or %q", ErrInvalidAlert, models.AlertAbove, models.AlertBelow)
+		}
+		if alert.Threshold == nil {
+			return nil, fmt.Errorf("%w: threshold is required for %s", ErrInvalidAlert, alert.Metric)
+		}
+		alert.Match = ""
+	default:
+		return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidAlert, alert.Metric)
+	}
+
+	if alert.PeriodSeconds < 0 {
+		return nil, fmt.Errorf("%w: period_seconds must not be negative", ErrInvalidAlert)
+	}
+	if alert.PeriodSeconds == 0 {
+		alert.PeriodSeconds = int(defaultWeatherAlertPeriod.Seconds())
+	}
+	alert.Enabled = true
+
+	existing, err := s.repo.ListWeather(ctx, alert.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAlertsPerUser {
+		return nil, fmt.Errorf("%w: at most %d alerts per user", ErrInvalidAlert, maxAlertsPerUser)
+	}
+
+	return s.repo.CreateWeather(ctx, alert)
+}
+
+func (s *AlertService) ListWeatherAlerts(ctx context.Context, userID int64) ([]models.WeatherAlert, error) {
+	return s.repo.ListWeather(ctx, userID)
+}
+
+func (s *AlertService) DeleteWeatherAlert(ctx context.Context, userID, id int64) error {
+	return s.repo.DeleteWeather(ctx, userID, id)
+}
+
+// EvaluateWeather проверяет правила города на новом значении погоды.
+// city — город из ключа кэша: в нём тот же регистр и написание, что в правилах.
+func (s *AlertService) EvaluateWeather(ctx context.Context, city string, weather *models.Weather) {
+	if s == nil || weather == nil {
+		return
+	}
+
+	rules, err := s.repo.WeatherRulesFor(ctx, city)
+	if err != nil {
+		log.Printf("Weather alerts for %s unavailable: %v", city, err)
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		observed, ok := matchWeather(rule, weather)
+		if !ok {
+			continue
+		}
+
+		fired, err := s.repo.TriggerWeather(ctx, rule.ID, now)
+		if err != nil {
+			log.Printf("Weather alert %d trigger failed: %v", rule.ID, err)
+			continue
+		}
+		if !fired {
+			continue
+		}
+
+		payload, _ := json.Marshal(map[string]interface{}{
+			"city":      weather.City,
+			"metric":    rule.Metric,
+			"operator":  rule.Operator,
+			"threshold": rule.Threshold,
+			"match":     rule.Match,
+			"value":     observed,
+		})
+		s.emit(ctx, models.AlertEvent{
+			RuleID:  rule.ID,
+			UserID:  rule.UserID,
+			Kind:    models.NotificationWeatherAlert,
+			Message: weatherAlertMessage(rule, weather.City, observed),
+			Payload: payload,
+		})
+	}
+}
+
+// matchWeather возвращает наблюдаемое значение метрики и выполнено ли условие
+func matchWeather(rule models.WeatherAlert, weather *models.Weather) (interface{}, bool) {
+	if rule.Metric == models.WeatherMetricCondition {
+		return weather.Condition, strings.Contains(strings.ToLower(weather.Condition), rule.Match)
+	}
+
+	metric, ok := weatherMetrics[rule.Metric]
+	if !ok || rule.Threshold == nil {
+		return nil, false
+	}
+	value := metric(weather)
+	if rule.Operator == models.AlertAbove {
+		return value, value > *rule.Threshold
+	}
+	return value, value < *rule.Threshold
+}
+
+func weatherAlertMessage(rule models.WeatherAlert, city string, observed interface{}) string {
+	if rule.Metric == models.WeatherMetricCondition {
+		return fmt.Sprintf("%s: %v", city, observed)
+	}
+	return fmt.Sprintf("%s: %s is %s %g (now %v)", city, rule.Metric, rule.Operator, *rule.Threshold, observed)
+}
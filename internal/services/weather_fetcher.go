@@ -1,19 +1,66 @@
 package services
 
 import (
+	"context"
 	"strings"
 
 	"service-info/internal/api"
+	"service-info/internal/breaker"
+	"service-info/internal/commands"
 	"service-info/internal/models"
+	"service-info/internal/quota"
 )
 
-type WeatherFetcher struct{}
+// WeatherCommand — команда админки для задач погоды
+var WeatherCommand = commands.Command{
+	Name:        "/weather",
+	TaskType:    "weather",
+	Description: "current weather for a city",
+	Args: []commands.ArgSpec{
+		{Name: "city", Required: true, Key: true, Help: "city name, e.g. Moscow", Normalize: commands.Lower, Validate: commands.NotEmpty},
+		{Name: "lang", Help: "two-letter response language, e.g. ru", Normalize: commands.Lower, Validate: commands.Letters(2)},
+	},
+	CacheKey: func(args models.TaskArgs) string {
+		return WeatherFetcher{}.CacheKey(args["city"], args["lang"])
+	},
+}
+
+type WeatherFetcher struct {
+	Quota    *quota.Tracker
+	Breakers *breaker.Set
+}
 
+// CacheKey — weather:<city> для языка по умолчанию и weather:<city>:<lang>
+// для остальных: ключи без языка по-прежнему адресуют основное наблюдение
+// в подписках, оповещениях и популярных запросах
 func (WeatherFetcher) CacheKey(params ...string) string {
 	city := strings.ToLower(strings.TrimSpace(params[0]))
+	if lang := weatherLang(params); lang != api.DefaultWeatherLang {
+		return "weather:" + city + ":" + lang
+	}
 	return "weather:" + city
 }
 
-func (WeatherFetcher) Fetch(params ...string) (*models.Weather, error) {
-	return api.FetchWeather(params[0])
+func (f WeatherFetcher) Fetch(ctx context.Context, params ...string) (*models.Weather, error) {
+	// Отказ по квоте — не ответ провайдера и не должен попадать в выключатель
+	if err := f.Quota.Acquire(ctx, quota.ProviderWeatherAPI, quota.PriorityEssential); err != nil {
+		return nil, &api.Error{Provider: quota.ProviderWeatherAPI, Kind: api.ErrQuotaExceeded, Err: err}
+	}
+	var weather *models.Weather
+	err := CallUpstream(ctx, f.Breakers, quota.ProviderWeatherAPI, func() error {
+		var err error
+		weather, err = api.FetchWeather(ctx, params[0], weatherLang(params))
+		return err
+	})
+	return weather, err
+}
+
+// weatherLang — язык из необязательного второго параметра (city, lang)
+func weatherLang(params []string) string {
+	if len(params) > 1 {
+		if lang := strings.ToLower(strings.TrimSpace(params[1])); lang != "" {
+			return lang
+		}
+	}
+	return api.DefaultWeatherLang
 }
@@ -0,0 +1,217 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Hub держит одну подписку на Redis-канал на реплику и раздаёт обновления
+// локальным подписчикам по ключам. У каждого подписчика ограниченный буфер:
+// если клиент не успевает читать, подписка закрывается, и клиент
+// переподключается, догоняя пропущенное из журнала.
+type Hub struct {
+	redis  *redis.Client
+	buffer int
+
+	mu   sync.RWMutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+func NewHub(redisClient *redis.Client, buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = 64
+	}
+	return &Hub{
+		redis:  redisClient,
+		buffer: buffer,
+		subs:   make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Run читает Redis-канал до отмены ctx
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.redis.Subscribe(ctx, Channel)
+	defer pubsub.Close()
+
+	log.Printf("📡 Stream hub subscribed to %s", Channel)
+	ch := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var u Update
+			if err := json.Unmarshal([]byte(msg.Payload), &u); err != nil {
+				log.Printf("Stream hub: invalid update: %v", err)
+				continue
+			}
+			h.dispatch(u)
+
+		case <-ctx.Done():
+			log.Println("Stream hub stopped")
+			return
+		}
+	}
+}
+
+func (h *Hub) dispatch(u Update) {
+	h.mu.RLock()
+	var slow []*Subscription
+	for sub := range h.subs[u.Key] {
+		select {
+		case sub.c <- u:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		log.Printf("⚠️ Stream subscriber too slow, dropping (key %s)", u.Key)
+		sub.Close()
+	}
+}
+
+// Subscribe создаёт подписку на ключи кэша
+func (h *Hub) Subscribe(keys ...string) *Subscription {
+	sub := &Subscription{
+		hub:  h,
+		c:    make(chan Update, h.buffer),
+		keys: make(map[string]struct{}),
+	}
+	sub.Add(keys...)
+	return sub
+}
+
+// Replay возвращает обновления ключей из журнала после записи afterID
+func (h *Hub) Replay(ctx context.Context, afterID string, keys []string) ([]Update, error) {
+	entries, err := h.redis.XRange(ctx, LogKey, "("+afterID, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		wanted[k] = struct{}{}
+	}
+
+	updates := make([]Update, 0)
+	for _, e := range entries {
+		key, _ := e.Values["key"].(string)
+		if _, ok := wanted[key]; !ok {
+			continue
+		}
+		data, _ := e.Values["data"].(string)
+		updates = append(updates, Update{ID: e.ID, Key: key, Data: json.RawMessage(data)})
+	}
+	return updates, nil
+}
+
+// Subscription — подписка одного клиента; набор ключей можно менять на лету
+type Subscription struct {
+	hub  *Hub
+	c    chan Update
+	once sync.Once
+
+	mu     sync.Mutex
+	keys   map[string]struct{}
+	closed bool
+}
+
+// C закрывается, когда подписка закрыта (в том числе хабом из-за медленного клиента)
+func (s *Subscription) C() <-chan Update {
+	return s.c
+}
+
+func (s *Subscription) Add(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	for _, k := range keys {
+		s.keys[k] = struct{}{}
+		if s.hub.subs[k] == nil {
+			s.hub.subs[k] = make(map[*Subscription]struct{})
+		}
+		s.hub.subs[k][s] = struct{}{}
+	}
+}
+
+func (s *Subscription) Remove(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	for _, k := range keys {
+		delete(s.keys, k)
+		s.hub.unlink(k, s)
+	}
+}
+
+// Keys — текущие ключи подписки
+func (s *Subscription) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.keys))
+	for k := range s.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closed = true
+
+		s.hub.mu.Lock()
+		for k := range s.keys {
+			s.hub.unlink(k, s)
+		}
+		s.hub.mu.Unlock()
+
+		close(s.c)
+	})
+}
+
+// unlink вызывается под h.mu
+func (h *Hub) unlink(key string, sub *Subscription) {
+	if subs, ok := h.subs[key]; ok {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.subs, key)
+		}
+	}
+}
+
+// After сравнивает идентификаторы записей Redis Stream ("<ms>-<seq>")
+func After(id, than string) bool {
+	ms1, seq1 := splitID(id)
+	ms2, seq2 := splitID(than)
+	if ms1 != ms2 {
+		return ms1 > ms2
+	}
+	return seq1 > seq2
+}
+
+func splitID(id string) (uint64, uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseUint(msPart, 10, 64)
+	seq, _ := strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
@@ -0,0 +1,65 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// LogKey — capped Redis Stream с последними обновлениями кэша, из него
+	// клиенты догоняют пропущенное по Last-Event-ID
+	LogKey = "cache-updates:log"
+	// Channel — pub/sub-канал, через который обновления доходят до всех реплик
+	Channel = "cache-updates"
+
+	logMaxLen = 10000
+)
+
+// Update — новое значение ключа кэша. ID — идентификатор записи в LogKey,
+// монотонно растёт и служит id события SSE.
+type Update struct {
+	ID   string          `json:"id"`
+	Key  string          `json:"key"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Publisher сообщает всем репликам о значениях, которые воркеры записали в Redis
+type Publisher struct {
+	redis *redis.Client
+}
+
+func NewPublisher(redisClient *redis.Client) *Publisher {
+	return &Publisher{redis: redisClient}
+}
+
+// Publish добавляет обновление в журнал и рассылает его подписчикам
+func (p *Publisher) Publish(ctx context.Context, key string, value interface{}) {
+	if p == nil {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Stream marshal error %s: %v", key, err)
+		return
+	}
+
+	id, err := p.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: LogKey,
+		MaxLen: logMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"key": key, "data": data},
+	}).Result()
+	if err != nil {
+		log.Printf("Stream XADD error %s: %v", key, err)
+		return
+	}
+
+	msg, _ := json.Marshal(Update{ID: id, Key: key, Data: data})
+	if err := p.redis.Publish(ctx, Channel, msg).Err(); err != nil {
+		log.Printf("Stream PUBLISH error %s: %v", key, err)
+	}
+}
@@ -45,10 +45,16 @@ import (
 	"strings"
 
 	"service-info/internal/api"
+	"service-info/internal/breaker"
 	"service-info/internal/models"
+	"service-info/internal/quota"
+	"service-info/internal/services"
 )
 
-type ExchangeWorkerHandler struct{}
+type ExchangeWorkerHandler struct {
+	Quota    *quota.Tracker
+	Breakers *breaker.Set
+}
 
 func (ExchangeWorkerHandler) Type() string {
 	return "exchange"
@@ -68,7 +74,15 @@ func (h ExchangeWorkerHandler) Handle(
 		if base == "" || target == "" {
 			return nil, "", fmt.Errorf("base and target required in command")
 		}
-		rate, err := api.FetchExchangeRate(base, target)
+		if err := h.Quota.Acquire(ctx, quota.ProviderFreeCurrency, quota.PriorityBackground); err != nil {
+			return nil, "", err
+		}
+		var rate *models.ExchangeRate
+		err := services.CallUpstream(ctx, h.Breakers, quota.ProviderFreeCurrency, func() error {
+			var err error
+			rate, err = api.FetchExchangeRate(ctx, base, target)
+			return err
+		})
 		if err != nil {
 			return nil, "", err
 		}
@@ -6,13 +6,19 @@ import (
 	"log"
 	"time"
 
+	"service-info/internal/services"
+
 	"github.com/redis/go-redis/v9"
 )
 
+// Observer вызывается после того, как воркер записал новое значение в Redis
+type Observer[T any] func(ctx context.Context, cacheKey string, value *T)
+
 type GenericWorker[T any] struct {
-	messages chan []byte
-	redis    *redis.Client
-	handler  WorkerHandler[T]
+	messages  chan []byte
+	redis     *redis.Client
+	handler   WorkerHandler[T]
+	observers []Observer[T]
 }
 
 type Worker interface {
@@ -23,11 +29,13 @@ func NewGenericWorker[T any](
 	messages chan []byte,
 	redis *redis.Client,
 	handler WorkerHandler[T],
+	observers ...Observer[T],
 ) *GenericWorker[T] {
 	return &GenericWorker[T]{
-		messages: messages,
-		redis:    redis,
-		handler:  handler,
+		messages:  messages,
+		redis:     redis,
+		handler:   handler,
+		observers: observers,
 	}
 }
 
@@ -49,7 +57,12 @@ func (w *GenericWorker[T]) Start(ctx context.Context) {
 				continue
 			}
 
-			w.writeToRedis(ctx, cacheKey, data)
+			if !w.writeToRedis(ctx, cacheKey, data) {
+				continue
+			}
+			for _, observe := range w.observers {
+				observe(ctx, cacheKey, result)
+			}
 
 		case <-ctx.Done():
 			log.Printf("%sWorker stopped", w.handler.Type())
@@ -58,11 +71,15 @@ func (w *GenericWorker[T]) Start(ctx context.Context) {
 	}
 }
 
-func (w *GenericWorker[T]) writeToRedis(ctx context.Context, key string, data []byte) {
+func (w *GenericWorker[T]) writeToRedis(ctx context.Context, key string, data []byte) bool {
 	ttl := time.Duration(w.handler.TTL()) * time.Second
-	if err := w.redis.Set(ctx, key, data, ttl).Err(); err != nil {
+	pipe := w.redis.TxPipeline()
+	pipe.Set(ctx, key, data, ttl)
+	pipe.Set(ctx, services.StaleKey(key), data, services.StaleTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
 		log.Printf("Redis SET error %s: %v", key, err)
-	} else {
-		log.Printf("%s cached in Redis: %s", w.handler.Type(), key)
+		return false
 	}
+	log.Printf("%s cached in Redis: %s", w.handler.Type(), key)
+	return true
 }
@@ -47,8 +47,16 @@ package workers
 import (
 	"context"
 	"log"
+	"strings"
 
+	"service-info/internal/breaker"
 	"service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/notify"
+	"service-info/internal/quota"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	"service-info/internal/stream"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -78,6 +86,12 @@ func StartAllWorkers(
 	ctx context.Context,
 	redisClient *redis.Client,
 	kafkaBundle *kafka.KafkaBundle,
+	quotaTracker *quota.Tracker,
+	breakers *breaker.Set,
+	requestLogRepo *repositories.RequestLogRepository,
+	alertService *services.AlertService,
+	dispatcher *notify.Dispatcher,
+	publisher *stream.Publisher,
 ) *WorkerBundle {
 
 	weatherCh := make(chan []byte, 100)
@@ -87,9 +101,25 @@ func StartAllWorkers(
 	singleConsumerToChannels(kafkaBundle.ExchangeConsumer, exchangeCh)
 	singleConsumerToChannels(kafkaBundle.PopularConsumer, weatherCh, exchangeCh)
 	go StartUserSyncer(redisClient, kafkaBundle.UserConsumer)
-
-	weatherWorker := NewGenericWorker(weatherCh, redisClient, WeatherWorkerHandler{})
-	exchangeWorker := NewGenericWorker(exchangeCh, redisClient, ExchangeWorkerHandler{})
+	go StartRequestLogSyncer(requestLogRepo, kafkaBundle.RequestConsumer)
+	go StartNotificationDispatcher(ctx, dispatcher, kafkaBundle.NotifyConsumer)
+
+	weatherWorker := NewGenericWorker(weatherCh, redisClient, WeatherWorkerHandler{Quota: quotaTracker, Breakers: breakers},
+		func(ctx context.Context, cacheKey string, weather *models.Weather) {
+			publisher.Publish(ctx, cacheKey, weather)
+			// Оповещения проверяются по основному наблюдению: условия
+			// вида «condition contains snow» написаны для языка по умолчанию
+			if city, lang, _ := strings.Cut(strings.TrimPrefix(cacheKey, "weather:"), ":"); lang == "" {
+				alertService.EvaluateWeather(ctx, city, weather)
+			}
+		},
+	)
+	exchangeWorker := NewGenericWorker(exchangeCh, redisClient, ExchangeWorkerHandler{Quota: quotaTracker, Breakers: breakers},
+		func(ctx context.Context, cacheKey string, rate *models.ExchangeRate) {
+			publisher.Publish(ctx, cacheKey, rate)
+			alertService.EvaluateExchange(ctx, rate)
+		},
+	)
 
 	go weatherWorker.Start(ctx)
 	go exchangeWorker.Start(ctx)
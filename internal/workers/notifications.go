@@ -0,0 +1,31 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/notify"
+)
+
+// StartNotificationDispatcher доставляет уведомления из топика notifications
+// в каналы пользователей
+func StartNotificationDispatcher(ctx context.Context, dispatcher *notify.Dispatcher, consumer *kafka.Consumer) {
+	if consumer == nil || dispatcher == nil {
+		return
+	}
+	consumer.Start(func(key, value []byte) {
+		var n models.Notification
+		if err := json.Unmarshal(value, &n); err != nil {
+			log.Printf("NotificationDispatcher: invalid notification: %v", err)
+			return
+		}
+		if n.ID == "" || n.UserID == 0 {
+			log.Println("⚠️ NotificationDispatcher: notification without id or user")
+			return
+		}
+		dispatcher.Deliver(ctx, n)
+	})
+}
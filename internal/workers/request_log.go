@@ -0,0 +1,32 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"service-info/internal/kafka"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+)
+
+// StartRequestLogSyncer складывает события запросов пользователей в request_log
+func StartRequestLogSyncer(repo *repositories.RequestLogRepository, consumer *kafka.Consumer) {
+	if consumer == nil || repo == nil {
+		return
+	}
+	consumer.Start(func(key, value []byte) {
+		var event models.RequestEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			log.Printf("RequestLogSyncer: invalid event: %v", err)
+			return
+		}
+		if event.Type == "" || len(event.Args) == 0 {
+			log.Println("⚠️ RequestLogSyncer: empty event")
+			return
+		}
+		if err := repo.Save(context.Background(), event); err != nil {
+			log.Printf("RequestLogSyncer: failed to save %s event: %v", event.Type, err)
+		}
+	})
+}
@@ -43,18 +43,29 @@ import (
 	"strings"
 
 	"service-info/internal/api"
+	"service-info/internal/breaker"
 	"service-info/internal/models"
+	"service-info/internal/quota"
+	"service-info/internal/services"
 )
 
-type WeatherWorkerHandler struct{}
+// WeatherWorkerHandler выполняет команды префетча, поэтому
+// расходует бюджет WeatherAPI с фоновым приоритетом.
+type WeatherWorkerHandler struct {
+	Quota    *quota.Tracker
+	Breakers *breaker.Set
+}
 
 func (WeatherWorkerHandler) Type() string {
 	return "weather"
 }
 
 // Handle поддерживает:
-//   - команды: {"type":"weather","args":{"city":"Moscow"}}
-//   - готовые объекты: {"city":"Moscow","temp":5.2,...}
+//   - команды: {"type":"weather","args":{"city":"Moscow","lang":"en"}}
+//   - готовые объекты: {"city":"Moscow","lang":"en","temp":5.2,...}
+//
+// Ключ кэша учитывает язык, чтобы наблюдение на одном языке
+// не перезаписало другое.
 func (h WeatherWorkerHandler) Handle(
 	ctx context.Context,
 	key, value []byte,
@@ -69,11 +80,19 @@ func (h WeatherWorkerHandler) Handle(
 		if city == "" {
 			return nil, "", fmt.Errorf("city is required in command")
 		}
-		weather, err := api.FetchWeather(city)
+		if err := h.Quota.Acquire(ctx, quota.ProviderWeatherAPI, quota.PriorityBackground); err != nil {
+			return nil, "", err
+		}
+		var weather *models.Weather
+		err := services.CallUpstream(ctx, h.Breakers, quota.ProviderWeatherAPI, func() error {
+			var err error
+			weather, err = api.FetchWeather(ctx, city, cmd.Args["lang"])
+			return err
+		})
 		if err != nil {
 			return nil, "", err
 		}
-		cacheKey := "weather:" + strings.ToLower(city)
+		cacheKey := services.WeatherFetcher{}.CacheKey(city, cmd.Args["lang"])
 		return weather, cacheKey, nil
 	}
 
@@ -84,7 +103,7 @@ func (h WeatherWorkerHandler) Handle(
 	if weather.City == "" {
 		return nil, "", fmt.Errorf("city is empty in weather object")
 	}
-	cacheKey := "weather:" + strings.ToLower(weather.City)
+	cacheKey := services.WeatherFetcher{}.CacheKey(weather.City, weather.Lang)
 	return &weather, cacheKey, nil
 }
 
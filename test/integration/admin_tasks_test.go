@@ -0,0 +1,216 @@
+// test/integration/admin_tasks_test.go
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"service-info/internal/handlers"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+)
+
+// seedTasks создаёт задачи разного возраста и возвращает их id по имени
+func seedTasks(t *testing.T, repo *repositories.AdminRepository, now time.Time) map[string]int {
+	t.Helper()
+
+	seed := []struct {
+		name  string
+		title string
+		args  models.TaskArgs
+		age   time.Duration
+	}{
+		{"old-moscow", "weather", models.TaskArgs{"city": "moscow"}, 40 * 24 * time.Hour},
+		{"moscow", "weather", models.TaskArgs{"city": "moscow"}, 48 * time.Hour},
+		{"london", "weather", models.TaskArgs{"city": "london"}, time.Hour},
+		{"old-usd-eur", "exchange", models.TaskArgs{"base": "USD", "target": "EUR"}, 40 * 24 * time.Hour},
+		{"usd-rub", "exchange", models.TaskArgs{"base": "USD", "target": "RUB"}, 0},
+	}
+
+	ids := make(map[string]int, len(seed))
+	for _, s := range seed {
+		id, err := repo.Save(context.Background(), models.Task{Title: s.title, Args: s.args, CreatedAt: now.Add(-s.age)})
+		if err != nil {
+			t.Fatalf("❌ Save %s failed: %v", s.name, err)
+		}
+		ids[s.name] = id
+	}
+	return ids
+}
+
+func taskIDs(tasks []models.Task) []int {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func sameIDs(got []int, want ...int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	got = append([]int(nil), got...)
+	want = append([]int(nil), want...)
+	sort.Ints(got)
+	sort.Ints(want)
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAdminRepository_ListFilters(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+	repo := repositories.NewAdminRepository(db)
+
+	now := time.Now()
+	ids := seedTasks(t, repo, now)
+
+	tests := []struct {
+		name   string
+		filter repositories.TaskFilter
+		want   []int
+	}{
+		{"no filter", repositories.TaskFilter{},
+			[]int{ids["old-moscow"], ids["moscow"], ids["london"], ids["old-usd-eur"], ids["usd-rub"]}},
+		{"by title", repositories.TaskFilter{Title: "weather"},
+			[]int{ids["old-moscow"], ids["moscow"], ids["london"]}},
+		{"by args", repositories.TaskFilter{Args: models.TaskArgs{"city": "moscow"}},
+			[]int{ids["old-moscow"], ids["moscow"]}},
+		{"by title and partial args", repositories.TaskFilter{Title: "exchange", Args: models.TaskArgs{"base": "USD"}},
+			[]int{ids["old-usd-eur"], ids["usd-rub"]}},
+		{"since", repositories.TaskFilter{Title: "weather", Since: now.Add(-72 * time.Hour)},
+			[]int{ids["moscow"], ids["london"]}},
+		{"until", repositories.TaskFilter{Until: now.Add(-30 * 24 * time.Hour)},
+			[]int{ids["old-moscow"], ids["old-usd-eur"]}},
+		{"window", repositories.TaskFilter{Since: now.Add(-72 * time.Hour), Until: now.Add(-30 * time.Minute)},
+			[]int{ids["moscow"], ids["london"]}},
+		{"no match", repositories.TaskFilter{Title: "weather", Args: models.TaskArgs{"city": "paris"}},
+			nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.Limit = 100
+			tasks, total, err := repo.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("❌ List failed: %v", err)
+			}
+			if total != len(tt.want) || !sameIDs(taskIDs(tasks), tt.want...) {
+				t.Errorf("❌ Expected %v (total %d), got %v (total %d)", tt.want, len(tt.want), taskIDs(tasks), total)
+			}
+		})
+	}
+
+	// Страницы идут от новых к старым, total не зависит от страницы
+	var pages [][]int
+	for offset := 0; offset < 6; offset += 2 {
+		tasks, total, err := repo.List(ctx, repositories.TaskFilter{Limit: 2, Offset: offset})
+		if err != nil {
+			t.Fatalf("❌ List failed: %v", err)
+		}
+		if total != 5 {
+			t.Fatalf("❌ Expected total 5 on every page, got %d", total)
+		}
+		pages = append(pages, taskIDs(tasks))
+	}
+	if len(pages[0]) != 2 || len(pages[1]) != 2 || len(pages[2]) != 1 {
+		t.Fatalf("❌ Expected pages of 2, 2 and 1 tasks, got %v", pages)
+	}
+	if pages[0][0] != ids["usd-rub"] || pages[0][1] != ids["london"] || pages[1][0] != ids["moscow"] {
+		t.Errorf("❌ Expected newest tasks first, got %v", pages)
+	}
+	if tasks, total, _ := repo.List(ctx, repositories.TaskFilter{Limit: 2, Offset: 10}); len(tasks) != 0 || total != 5 {
+		t.Errorf("❌ Expected empty page past the end with total 5, got %d tasks, total %d", len(tasks), total)
+	}
+}
+
+func TestAdminRepository_DeleteMatching(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+	repo := repositories.NewAdminRepository(db)
+
+	now := time.Now()
+	ids := seedTasks(t, repo, now)
+
+	remaining := func() []int {
+		tasks, _, err := repo.List(ctx, repositories.TaskFilter{Limit: 100})
+		if err != nil {
+			t.Fatalf("❌ List failed: %v", err)
+		}
+		return taskIDs(tasks)
+	}
+
+	if _, err := repo.DeleteMatching(ctx, "", time.Time{}); err == nil {
+		t.Fatal("❌ Expected empty bulk delete filter to be rejected")
+	}
+	if got := remaining(); len(got) != 5 {
+		t.Fatalf("❌ Rejected bulk delete must not remove tasks, left %v", got)
+	}
+
+	steps := []struct {
+		name      string
+		title     string
+		olderThan time.Time
+		deleted   int64
+		left      []int
+	}{
+		{"title and age", "weather", now.Add(-30 * 24 * time.Hour), 1,
+			[]int{ids["moscow"], ids["london"], ids["old-usd-eur"], ids["usd-rub"]}},
+		{"age only", "", now.Add(-30 * 24 * time.Hour), 1,
+			[]int{ids["moscow"], ids["london"], ids["usd-rub"]}},
+		{"title only", "exchange", time.Time{}, 1,
+			[]int{ids["moscow"], ids["london"]}},
+		{"nothing matches", "exchange", time.Time{}, 0,
+			[]int{ids["moscow"], ids["london"]}},
+	}
+	for _, s := range steps {
+		deleted, err := repo.DeleteMatching(ctx, s.title, s.olderThan)
+		if err != nil {
+			t.Fatalf("❌ %s: DeleteMatching failed: %v", s.name, err)
+		}
+		if got := remaining(); deleted != s.deleted || !sameIDs(got, s.left...) {
+			t.Errorf("❌ %s: expected %d deleted and %v left, got %d and %v", s.name, s.deleted, s.left, deleted, got)
+		}
+	}
+
+	// DELETE /admin/tasks: пустой фильтр и неверный возраст — 400
+	adminHandler := handlers.NewAdminHandler(services.NewAdminService(repo), newTestRegistry(t))
+	srv := httptest.NewServer(testutils.WithSpec(t, http.HandlerFunc(adminHandler.DeleteTasks)))
+	defer srv.Close()
+
+	del := func(query string) (int, map[string]int64) {
+		req, _ := http.NewRequest("DELETE", srv.URL+"/admin/tasks?"+query, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("❌ DELETE /admin/tasks?%s failed: %v", query, err)
+		}
+		defer resp.Body.Close()
+		var body map[string]int64
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	for _, query := range []string{"", "title=%20", "older_than=-1h", "older_than=month"} {
+		if status, _ := del(query); status != http.StatusBadRequest {
+			t.Errorf("❌ Expected 400 for %q, got %d", query, status)
+		}
+	}
+	if status, body := del("title=weather&older_than=24h"); status != http.StatusOK || body["deleted"] != 1 {
+		t.Errorf("❌ Expected 1 task deleted, got %d %v", status, body)
+	}
+	if got := remaining(); !sameIDs(got, ids["london"]) {
+		t.Errorf("❌ Expected only london left, got %v", got)
+	}
+}
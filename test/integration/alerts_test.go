@@ -0,0 +1,108 @@
+// test/integration/alerts_test.go
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+)
+
+func TestExchangeAlert_FiresOnCrossing(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+
+	producer := &capturingProducer{}
+	alertService := services.NewAlertService(repositories.NewAlertRepository(db), producer)
+
+	rule, err := alertService.CreateExchangeAlert(ctx, models.ExchangeAlert{
+		UserID:    42,
+		Base:      "usd",
+		Target:    "rub",
+		Direction: "above",
+		Threshold: 100,
+	})
+	if err != nil {
+		t.Fatalf("❌ CreateExchangeAlert failed: %v", err)
+	}
+	if rule.Base != "USD" || rule.CooldownSeconds != 3600 {
+		t.Fatalf("❌ Expected normalized rule, got %+v", rule)
+	}
+
+	// 95 → 101 — пересечение; 102 — уже выше уровня, повторно не срабатывает
+	for _, rate := range []float64{95, 101, 102} {
+		alertService.EvaluateExchange(ctx, &models.ExchangeRate{Base: "usd", Target: "rub", Rate: rate})
+	}
+
+	events, err := alertService.ListEvents(ctx, 42, 10)
+	if err != nil {
+		t.Fatalf("❌ ListEvents failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("❌ Expected exactly 1 alert event, got %d", len(events))
+	}
+	if len(producer.sent) != 1 {
+		t.Fatalf("❌ Expected 1 notification, got %d", len(producer.sent))
+	}
+	n, ok := producer.sent[0].(models.Notification)
+	if !ok || n.UserID != 42 || n.EventID != events[0].ID {
+		t.Errorf("❌ Unexpected notification %+v", producer.sent[0])
+	}
+
+	// 99 → 103 — новое пересечение, но идёт cooldown
+	for _, rate := range []float64{99, 103} {
+		alertService.EvaluateExchange(ctx, &models.ExchangeRate{Base: "USD", Target: "RUB", Rate: rate})
+	}
+	if events, _ := alertService.ListEvents(ctx, 42, 10); len(events) != 1 {
+		t.Errorf("❌ Expected cooldown to suppress the alert, got %d events", len(events))
+	}
+}
+
+func TestWeatherAlert_OncePerPeriod(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+
+	producer := &capturingProducer{}
+	alertService := services.NewAlertService(repositories.NewAlertRepository(db), producer)
+
+	threshold := -20.0
+	if _, err := alertService.CreateWeatherAlert(ctx, models.WeatherAlert{
+		UserID:    7,
+		City:      "Moscow",
+		Metric:    "temp",
+		Operator:  "below",
+		Threshold: &threshold,
+	}); err != nil {
+		t.Fatalf("❌ CreateWeatherAlert failed: %v", err)
+	}
+	if _, err := alertService.CreateWeatherAlert(ctx, models.WeatherAlert{
+		UserID:   7,
+		City:     "moscow",
+		Metric:   "condition",
+		Operator: "contains",
+		Match:    "Snow",
+	}); err != nil {
+		t.Fatalf("❌ CreateWeatherAlert failed: %v", err)
+	}
+
+	// Оба правила выполняются дважды, но в одном периоде каждое срабатывает один раз
+	for i := 0; i < 2; i++ {
+		alertService.EvaluateWeather(ctx, "moscow", &models.Weather{City: "Moscow", Temp: -25, Condition: "Heavy snow"})
+	}
+
+	events, err := alertService.ListEvents(ctx, 7, 10)
+	if err != nil {
+		t.Fatalf("❌ ListEvents failed: %v", err)
+	}
+	if len(events) != 2 || len(producer.sent) != 2 {
+		t.Fatalf("❌ Expected 2 events and 2 notifications, got %d and %d", len(events), len(producer.sent))
+	}
+	for _, e := range events {
+		if e.Kind != models.NotificationWeatherAlert {
+			t.Errorf("❌ Unexpected event kind %q", e.Kind)
+		}
+	}
+}
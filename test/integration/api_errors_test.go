@@ -0,0 +1,118 @@
+// test/integration/api_errors_test.go
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service-info/internal/api"
+	"service-info/internal/breaker"
+	"service-info/internal/quota"
+	"service-info/internal/services"
+)
+
+// fakeProvider отвечает заданным статусом и телом и считает обращения
+func fakeProvider(t *testing.T, status int, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestAPI_WeatherErrorKinds(t *testing.T) {
+	t.Setenv("WEATHERAPI_KEY", "test-key")
+
+	cases := []struct {
+		name      string
+		status    int
+		body      string
+		kind      error
+		wantCalls int32
+	}{
+		{"unknown city", 400, `{"error":{"code":1006,"message":"No matching location found."}}`, api.ErrNotFound, 1},
+		{"invalid key", 401, `{"error":{"code":2006,"message":"API key is invalid."}}`, api.ErrUnauthorized, 1},
+		{"monthly quota", 403, `{"error":{"code":2007,"message":"API key has exceeded calls per month quota."}}`, api.ErrQuotaExceeded, 1},
+		{"outage is retried", 502, `bad gateway`, api.ErrUpstreamUnavailable, 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, calls := fakeProvider(t, tc.status, tc.body)
+			t.Setenv("WEATHERAPI_URL", srv.URL)
+
+			_, err := api.FetchWeather(context.Background(), "Moscow", "")
+			if !errors.Is(err, tc.kind) {
+				t.Fatalf("❌ Expected %v, got %v", tc.kind, err)
+			}
+			var apiErr *api.Error
+			if !errors.As(err, &apiErr) || apiErr.Provider != "weatherapi" {
+				t.Errorf("❌ Expected *api.Error from weatherapi, got %#v", err)
+			}
+			if got := calls.Load(); got != tc.wantCalls {
+				t.Errorf("❌ Expected %d provider calls, got %d", tc.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestAPI_ExchangeUnknownCurrency(t *testing.T) {
+	t.Setenv("FREECURRENCY_API_KEY", "test-key")
+
+	srv, _ := fakeProvider(t, http.StatusOK, `{"data":{"EUR":0.92}}`)
+	t.Setenv("FREECURRENCY_API_URL", srv.URL)
+
+	if _, err := api.FetchExchangeRate(context.Background(), "USD", "XYZ"); !errors.Is(err, api.ErrNotFound) {
+		t.Fatalf("❌ Expected ErrNotFound for unknown target, got %v", err)
+	}
+	if rate, err := api.FetchExchangeRate(context.Background(), "USD", "EUR"); err != nil || rate.Rate != 0.92 {
+		t.Fatalf("❌ Expected rate 0.92, got %+v (%v)", rate, err)
+	}
+}
+
+func TestAPI_ContextCancelsUpstreamCall(t *testing.T) {
+	t.Setenv("WEATHERAPI_KEY", "test-key")
+
+	// Провайдер «висит», пока клиент не оборвёт соединение
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("WEATHERAPI_URL", srv.URL)
+
+	breakers := breaker.NewSet(map[string]breaker.Settings{
+		quota.ProviderWeatherAPI: {FailureThreshold: 1},
+	}, api.Retryable)
+	fetcher := services.WeatherFetcher{Breakers: breakers}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := fetcher.Fetch(ctx, "Moscow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("❌ Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("❌ Upstream call not cancelled, took %v", elapsed)
+	}
+	if errors.Is(err, api.ErrUpstreamUnavailable) {
+		t.Errorf("❌ Cancelled call must not look like an upstream outage: %v", err)
+	}
+	if state := breakers.Providers()[0].State; state != breaker.StateClosed {
+		t.Errorf("❌ Cancelled call must not trip the breaker, got %s", state)
+	}
+}
@@ -0,0 +1,214 @@
+// test/integration/breaker_test.go
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service-info/internal/api"
+	"service-info/internal/breaker"
+	"service-info/internal/models"
+	"service-info/internal/quota"
+	"service-info/internal/services"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// flakyWeatherAPI отвечает 503, пока down, и нормальной погодой после
+func flakyWeatherAPI(t *testing.T, down *atomic.Bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"location":{"name":"Moscow"},"current":{"temp_c":-3}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+	t.Setenv("WEATHERAPI_KEY", "test-key")
+	var down atomic.Bool
+	down.Store(true)
+	srv, calls := flakyWeatherAPI(t, &down)
+	t.Setenv("WEATHERAPI_URL", srv.URL)
+
+	breakers := breaker.NewSet(map[string]breaker.Settings{
+		quota.ProviderWeatherAPI: {FailureThreshold: 2, OpenTimeout: 200 * time.Millisecond},
+	}, api.Retryable)
+	fetcher := services.WeatherFetcher{Breakers: breakers}
+
+	for i := 0; i < 2; i++ {
+		if _, err := fetcher.Fetch(context.Background(), "Moscow"); !errors.Is(err, api.ErrUpstreamUnavailable) {
+			t.Fatalf("❌ Expected upstream failure, got %v", err)
+		}
+	}
+	if state := breakers.Providers()[0].State; state != breaker.StateOpen {
+		t.Fatalf("❌ Expected open breaker after 2 failures, got %s", state)
+	}
+
+	// Разомкнутый выключатель отвечает сразу, не обращаясь к провайдеру
+	before := calls.Load()
+	_, err := fetcher.Fetch(context.Background(), "Moscow")
+	if !errors.Is(err, breaker.ErrOpen) || !errors.Is(err, api.ErrUpstreamUnavailable) {
+		t.Fatalf("❌ Expected ErrOpen as upstream unavailable, got %v", err)
+	}
+	if calls.Load() != before {
+		t.Errorf("❌ Provider called while breaker is open")
+	}
+
+	// После OpenTimeout пробный запрос проходит и замыкает выключатель
+	down.Store(false)
+	time.Sleep(250 * time.Millisecond)
+	if weather, err := fetcher.Fetch(context.Background(), "Moscow"); err != nil || weather.City != "Moscow" {
+		t.Fatalf("❌ Expected probe to succeed, got %+v (%v)", weather, err)
+	}
+	if state := breakers.Providers()[0].State; state != breaker.StateClosed {
+		t.Errorf("❌ Expected closed breaker after successful probe, got %s", state)
+	}
+}
+
+func TestBreaker_ServesStaleCache(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	t.Setenv("WEATHERAPI_KEY", "test-key")
+	var down atomic.Bool
+	down.Store(true)
+	srv, _ := flakyWeatherAPI(t, &down)
+	t.Setenv("WEATHERAPI_URL", srv.URL)
+
+	key := services.WeatherFetcher{}.CacheKey("StaleTown")
+	data, _ := json.Marshal(models.Weather{City: "StaleTown", Temp: 7})
+	rdb.Del(ctx, key)
+	rdb.Set(ctx, services.StaleKey(key), data, time.Minute)
+	defer rdb.Del(ctx, services.StaleKey(key))
+
+	breakers := breaker.NewSet(map[string]breaker.Settings{
+		quota.ProviderWeatherAPI: {FailureThreshold: 1, OpenTimeout: time.Minute},
+	}, api.Retryable)
+	service := services.NewCacheService(rdb, nil, services.WeatherFetcher{Breakers: breakers})
+
+	// Первый запрос размыкает выключатель, второй не доходит до провайдера — оба получают старую копию
+	for i := 0; i < 2; i++ {
+		weather, err := service.Get(ctx, "StaleTown")
+		if err != nil || weather.Temp != 7 {
+			t.Fatalf("❌ Expected stale weather, got %+v (%v)", weather, err)
+		}
+	}
+
+	if _, err := service.Get(ctx, "NoStaleCopy"); !errors.Is(err, api.ErrUpstreamUnavailable) {
+		t.Errorf("❌ Expected upstream unavailable without stale copy, got %v", err)
+	}
+}
+
+func TestBreaker_CountsClientTimeouts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	breakers := breaker.NewSet(map[string]breaker.Settings{
+		quota.ProviderWeatherAPI: {FailureThreshold: 2, OpenTimeout: time.Minute},
+	}, api.Retryable)
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	call := func(ctx context.Context) error {
+		return breakers.Do(ctx, quota.ProviderWeatherAPI, func() error {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return &api.Error{Provider: quota.ProviderWeatherAPI, Kind: api.ErrUpstreamUnavailable, Err: err}
+			}
+			resp.Body.Close()
+			return nil
+		})
+	}
+
+	// Отмена на стороне вызывающего о провайдере ничего не говорит
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if err := call(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("❌ Expected caller deadline, got %v", err)
+		}
+	}
+	if status := breakers.Providers()[0]; status.State != breaker.StateClosed || status.Failures != 0 {
+		t.Fatalf("❌ Caller deadlines must not count as failures, got %+v", status)
+	}
+
+	// Таймаут HTTP-клиента при живом ctx — сбой провайдера, хоть и выглядит как DeadlineExceeded
+	for i := 0; i < 2; i++ {
+		err := call(context.Background())
+		if !errors.Is(err, api.ErrUpstreamUnavailable) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("❌ Expected client timeout as upstream failure, got %v", err)
+		}
+	}
+	if state := breakers.Providers()[0].State; state != breaker.StateOpen {
+		t.Errorf("❌ Expected open breaker after 2 client timeouts, got %s", state)
+	}
+}
+
+func TestBreaker_IgnoresQuotaRefusals(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	now := time.Now().UTC()
+	keys := []string{
+		"quota:" + quota.ProviderWeatherAPI + ":d:" + now.Format("20060102"),
+		"quota:" + quota.ProviderWeatherAPI + ":m:" + now.Format("200601"),
+	}
+	rdb.Del(ctx, keys...)
+	defer rdb.Del(ctx, keys...)
+
+	t.Setenv("WEATHERAPI_KEY", "test-key")
+	var down atomic.Bool
+	down.Store(true)
+	srv, calls := flakyWeatherAPI(t, &down)
+	t.Setenv("WEATHERAPI_URL", srv.URL)
+
+	breakers := breaker.NewSet(map[string]breaker.Settings{
+		quota.ProviderWeatherAPI: {FailureThreshold: 2, OpenTimeout: time.Minute},
+	}, api.Retryable)
+	tracker := quota.NewTracker(rdb, map[string]quota.Budget{quota.ProviderWeatherAPI: {Daily: 1}}, 0)
+	fetcher := services.WeatherFetcher{Quota: tracker, Breakers: breakers}
+
+	if _, err := fetcher.Fetch(ctx, "Moscow"); !errors.Is(err, api.ErrUpstreamUnavailable) {
+		t.Fatalf("❌ Expected upstream failure, got %v", err)
+	}
+	before := calls.Load()
+
+	// Отказ по квоте не доходит до провайдера и не сбрасывает счёт сбоев
+	if _, err := fetcher.Fetch(ctx, "Moscow"); !errors.Is(err, api.ErrQuotaExceeded) {
+		t.Fatalf("❌ Expected quota refusal, got %v", err)
+	}
+	if calls.Load() != before {
+		t.Errorf("❌ Provider called after quota refusal")
+	}
+	if status := breakers.Providers()[0]; status.State != breaker.StateClosed || status.Failures != 1 {
+		t.Errorf("❌ Expected 1 failure kept after quota refusal, got %+v", status)
+	}
+}
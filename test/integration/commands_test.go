@@ -0,0 +1,201 @@
+// test/integration/commands_test.go
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"service-info/internal/commands"
+	"service-info/internal/handlers"
+	"service-info/internal/models"
+	"service-info/internal/services"
+)
+
+func newTestRegistry(t *testing.T) *commands.Registry {
+	t.Helper()
+	registry, err := commands.NewRegistry(services.WeatherCommand, services.ExchangeCommand)
+	if err != nil {
+		t.Fatalf("❌ NewRegistry failed: %v", err)
+	}
+	return registry
+}
+
+func TestRegistry_ParseTask(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	tests := []struct {
+		text     string
+		wantType string
+		wantArgs models.TaskArgs
+		wantErr  error
+		wantMsg  string
+	}{
+		{text: "/weather  Moscow ", wantType: "weather", wantArgs: models.TaskArgs{"city": "moscow"}},
+		{text: "/weather Moscow EN", wantType: "weather", wantArgs: models.TaskArgs{"city": "moscow", "lang": "en"}},
+		{text: "/exchange usd eur", wantType: "exchange", wantArgs: models.TaskArgs{"base": "USD", "target": "EUR"}},
+		{text: "/weather", wantErr: commands.ErrInvalidArgs, wantMsg: "usage: /weather <city> [lang]"},
+		{text: "/weather Moscow en extra", wantErr: commands.ErrInvalidArgs, wantMsg: "usage: /weather <city> [lang]"},
+		{text: "/exchange usd", wantErr: commands.ErrInvalidArgs, wantMsg: "usage: /exchange <base> <target>"},
+		{text: "/weather Moscow english", wantErr: commands.ErrInvalidArgs, wantMsg: "lang: must be 2 letters"},
+		{text: "/exchange us1 eur", wantErr: commands.ErrInvalidArgs, wantMsg: "base: must be 3 latin letters"},
+		{text: "/forecast Moscow", wantErr: commands.ErrUnknownCommand},
+		{text: "   ", wantErr: commands.ErrInvalidArgs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			task, err := registry.ParseTask(tt.text)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("❌ Expected %v, got %v", tt.wantErr, err)
+				}
+				if !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Errorf("❌ Expected error to mention %q, got %q", tt.wantMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("❌ ParseTask failed: %v", err)
+			}
+			if task.Title != tt.wantType || len(task.Args) != len(tt.wantArgs) {
+				t.Fatalf("❌ Expected %s %v, got %s %v", tt.wantType, tt.wantArgs, task.Title, task.Args)
+			}
+			for k, v := range tt.wantArgs {
+				if task.Args[k] != v {
+					t.Errorf("❌ Expected %s=%q, got %q", k, v, task.Args[k])
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_RejectsBadRegistrations(t *testing.T) {
+	if _, err := commands.NewRegistry(services.WeatherCommand, services.WeatherCommand); err == nil {
+		t.Error("❌ Expected duplicate command to be rejected")
+	}
+	forecast := services.WeatherCommand
+	forecast.Name = "/forecast"
+	if _, err := commands.NewRegistry(services.WeatherCommand, forecast); err == nil {
+		t.Error("❌ Expected duplicate task type to be rejected")
+	}
+	if _, err := commands.NewRegistry(commands.Command{Name: "/help", TaskType: "help"}); err == nil {
+		t.Error("❌ Expected /help registration to be rejected")
+	}
+	if _, err := commands.NewRegistry(commands.Command{Name: "forecast", TaskType: "forecast"}); err == nil {
+		t.Error("❌ Expected command without leading / to be rejected")
+	}
+	if _, err := commands.NewRegistry(commands.Command{Name: "/forecast"}); err == nil {
+		t.Error("❌ Expected command without type to be rejected")
+	}
+
+	registry := newTestRegistry(t)
+	if err := registry.Register(services.ExchangeCommand); err == nil {
+		t.Error("❌ Expected late duplicate registration to be rejected")
+	}
+	if err := registry.Register(forecast); err == nil {
+		t.Error("❌ Expected late duplicate task type to be rejected")
+	}
+	if cmd, _ := registry.ByType("weather"); cmd.Name != "/weather" {
+		t.Errorf("❌ Expected weather type to stay bound to /weather, got %s", cmd.Name)
+	}
+	if cmds := registry.Commands(); len(cmds) != 2 {
+		t.Errorf("❌ Expected registry untouched by rejected registration, got %d commands", len(cmds))
+	}
+}
+
+func TestRegistry_Help(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	all, err := registry.Help("")
+	if err != nil {
+		t.Fatalf("❌ Help failed: %v", err)
+	}
+	for _, want := range []string{
+		"/exchange <base> <target> — exchange rate between two currencies",
+		"/weather <city> [lang] — current weather for a city",
+		"/help [command]",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("❌ Expected help to contain %q, got:\n%s", want, all)
+		}
+	}
+	if strings.Index(all, "/exchange") > strings.Index(all, "/weather") {
+		t.Errorf("❌ Expected commands sorted by name, got:\n%s", all)
+	}
+
+	one, err := registry.Help("weather")
+	if err != nil {
+		t.Fatalf("❌ Help(weather) failed: %v", err)
+	}
+	if !strings.Contains(one, "city: city name, e.g. Moscow") || strings.Contains(one, "/exchange") {
+		t.Errorf("❌ Unexpected help for /weather:\n%s", one)
+	}
+
+	if _, err := registry.Help("/forecast"); !errors.Is(err, commands.ErrUnknownCommand) {
+		t.Errorf("❌ Expected ErrUnknownCommand, got %v", err)
+	}
+}
+
+func TestAdminHandler_HelpAndCommands(t *testing.T) {
+	// /help и /admin/commands не ходят в базу
+	adminHandler := handlers.NewAdminHandler(nil, newTestRegistry(t))
+
+	router := http.NewServeMux()
+	router.HandleFunc("POST /admin/popular", adminHandler.CreatePopular)
+	router.HandleFunc("GET /admin/commands", adminHandler.ListCommands)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	post := func(text string) (int, map[string]any) {
+		payload, _ := json.Marshal(map[string]string{"text": text})
+		resp, err := http.Post(srv.URL+"/admin/popular", "application/json", bytes.NewReader(payload))
+		if err != nil {
+			t.Fatalf("❌ POST /admin/popular failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	status, body := post("/help exchange")
+	if help, _ := body["help"].(string); status != http.StatusOK || !strings.HasPrefix(help, "/exchange <base> <target>") {
+		t.Errorf("❌ Unexpected /help exchange response %d %v", status, body)
+	}
+	if status, _ := post("/help forecast"); status != http.StatusBadRequest {
+		t.Errorf("❌ Expected 400 for help on unknown command, got %d", status)
+	}
+	if status, _ := post("/weather"); status != http.StatusBadRequest {
+		t.Errorf("❌ Expected 400 for missing required arg, got %d", status)
+	}
+
+	resp, err := http.Get(srv.URL + "/admin/commands")
+	if err != nil {
+		t.Fatalf("❌ GET /admin/commands failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Commands []struct {
+			Name  string             `json:"name"`
+			Type  string             `json:"type"`
+			Usage string             `json:"usage"`
+			Args  []commands.ArgSpec `json:"args"`
+		} `json:"commands"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("❌ /admin/commands JSON decode failed: %v", err)
+	}
+	if len(list.Commands) != 2 || list.Commands[0].Name != "/exchange" || list.Commands[1].Name != "/weather" {
+		t.Fatalf("❌ Expected /exchange and /weather, got %+v", list.Commands)
+	}
+	weather := list.Commands[1]
+	if weather.Type != "weather" || weather.Usage != "/weather <city> [lang]" || len(weather.Args) != 2 || !weather.Args[0].Key {
+		t.Errorf("❌ Unexpected /weather description %+v", weather)
+	}
+}
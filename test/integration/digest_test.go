@@ -0,0 +1,94 @@
+// test/integration/digest_test.go
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestDigest_SentOncePerLocalDay(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+
+	// Redis не нужен: промах кэша уходит в Postgres и fetcher
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	weather := services.NewCacheService[models.Weather](rdb, nil, &testutils.StubWeatherFetcher{
+		Weather: models.Weather{Temp: 21, FeelsLike: 19, Condition: "Clear", WindKPH: 7},
+	})
+	prefs := services.NewPreferencesService(repositories.NewPreferencesRepository(db), rdb, weather, nil, time.Minute)
+	if _, err := prefs.Save(ctx, models.UserPreferences{UserID: 5, FavoriteCities: []string{"Tokyo"}}); err != nil {
+		t.Fatalf("❌ Preferences Save failed: %v", err)
+	}
+
+	repo := repositories.NewDigestRepository(db)
+	producer := &capturingProducer{}
+	digestService := services.NewDigestService(repo, prefs, producer)
+
+	if _, err := digestService.Save(ctx, models.DigestSchedule{UserID: 5, TimeOfDay: "25:00"}); err == nil {
+		t.Fatal("❌ Expected invalid time_of_day to be rejected")
+	}
+	if _, err := digestService.Save(ctx, models.DigestSchedule{UserID: 5, TimeOfDay: "00:00", Timezone: "Asia/Tokyo", Enabled: true}); err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+
+	now := time.Now()
+	due, err := digestService.Due(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("❌ Due failed: %v", err)
+	}
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	date := now.In(tokyo).Format("2006-01-02")
+	if len(due) != 1 || due[0].LastSentDate != date {
+		t.Fatalf("❌ Expected schedule due for Tokyo local date, got %+v", due)
+	}
+
+	// Сводка не собралась — день не сгорает
+	brokenDB, _ := sql.Open("postgres", "host=127.0.0.1 port=1 user=none dbname=none sslmode=disable connect_timeout=1")
+	defer brokenDB.Close()
+	brokenPrefs := services.NewPreferencesService(repositories.NewPreferencesRepository(brokenDB), rdb, weather, nil, time.Minute)
+	if sent, err := services.NewDigestService(repo, brokenPrefs, producer).Send(ctx, 0, due[0]); err == nil || sent {
+		t.Fatalf("❌ Expected Send to fail without preferences, got sent=%v err=%v", sent, err)
+	}
+	if due, _ := digestService.Due(ctx, now, 10); len(due) != 1 {
+		t.Fatalf("❌ Expected the day to stay due after a failed Send, got %+v", due)
+	}
+
+	// Первый Send забирает день, повторный (как после перезапуска) — уже нет
+	if sent, err := digestService.Send(ctx, 0, due[0]); err != nil || !sent {
+		t.Fatalf("❌ Send failed: sent=%v err=%v", sent, err)
+	}
+	if sent, err := digestService.Send(ctx, 0, due[0]); err != nil || sent {
+		t.Errorf("❌ Expected the day to be sent only once, got sent=%v err=%v", sent, err)
+	}
+	if due, _ := digestService.Due(ctx, now, 10); len(due) != 0 {
+		t.Errorf("❌ Expected nothing due after sending, got %+v", due)
+	}
+
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	if len(producer.sent) != 1 {
+		t.Fatalf("❌ Expected exactly 1 digest notification, got %d", len(producer.sent))
+	}
+	n, ok := producer.sent[0].(models.Notification)
+	if !ok {
+		t.Fatalf("❌ Expected models.Notification, got %T", producer.sent[0])
+	}
+	if n.ID != "digest:5:"+date || n.UserID != 5 || n.Kind != models.NotificationDigest {
+		t.Errorf("❌ Unexpected digest notification %+v", n)
+	}
+	wantText := "Сводка на " + date + "\n🌡 Tokyo: 21°C (ощущается как 19°C), Clear, ветер 7 км/ч"
+	if n.Message != wantText {
+		t.Errorf("❌ Unexpected digest text:\n%s\nwant:\n%s", n.Message, wantText)
+	}
+}
@@ -45,6 +45,12 @@ func TestGetExchangeRate_KafkaToRedis(t *testing.T) {
 		&kafka.KafkaBundle{
 			ExchangeConsumer: consumer,
 		},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	time.Sleep(1 * time.Second)
@@ -59,11 +65,11 @@ func TestGetExchangeRate_KafkaToRedis(t *testing.T) {
 		services.ExchangeFetcher{},
 	)
 
-	exchangeHandler := handlers.NewExchangeHandler(exchangeService)
+	exchangeHandler := handlers.NewExchangeHandler(exchangeService, nil, nil)
 
 	router := http.NewServeMux()
 	router.HandleFunc("/exchange", exchangeHandler.GetRate)
-	srv := httptest.NewServer(router)
+	srv := httptest.NewServer(testutils.WithSpec(t, router))
 	defer srv.Close()
 
 	req, _ := http.NewRequest(
@@ -0,0 +1,90 @@
+// test/integration/graphql_test.go
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"service-info/internal/graph"
+	"service-info/internal/handlers"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGraphQL_DedupAndLimits(t *testing.T) {
+	// Redis не нужен: промах кэша (в том числе недоступный Redis) уходит в fetcher
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	fetcher := &testutils.StubWeatherFetcher{Weather: models.Weather{Temp: 12.5}, Delay: 50 * time.Millisecond}
+	city := "GraphQLTown" + time.Now().Format("150405.000")
+	weather := services.NewCacheService[models.Weather](rdb, nil, fetcher)
+
+	schema := graph.NewSchema(weather, nil, nil, nil, nil, graph.Limits{MaxDepth: 3, MaxComplexity: 5})
+	handler := handlers.NewGraphQLHandler(schema)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.UserIDKey, int64(42))
+		handler.Query(w, r.WithContext(ctx))
+	}))
+	defer srv.Close()
+
+	query := func(q string) map[string]any {
+		body, _ := json.Marshal(map[string]string{"query": q})
+		resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("❌ POST /graphql failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var out map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("❌ GraphQL JSON decode failed: %v", err)
+		}
+		return out
+	}
+
+	// Один и тот же ключ под разными алиасами и регистром — один вызов fetcher
+	out := query(`{ a: weather(city: "` + city + `") { city tempCelsius }
+		b: weather(city: "` + strings.ToLower(city) + `") { city }
+		c: weather(city: "` + city + `") { updatedAt } }`)
+	if out["errors"] != nil {
+		t.Fatalf("❌ Unexpected errors: %v", out["errors"])
+	}
+	data := out["data"].(map[string]any)
+	if data["a"].(map[string]any)["tempCelsius"] != 12.5 || data["b"] == nil || data["c"] == nil {
+		t.Fatalf("❌ Unexpected data: %v", data)
+	}
+	if n := fetcher.Calls(); n != 1 {
+		t.Fatalf("❌ Expected 1 upstream fetch per request, got %d", n)
+	}
+
+	// Бюджет сложности: шестое поле с данными получает ошибку
+	var fields []string
+	for i := 0; i < 6; i++ {
+		fields = append(fields, "w"+string(rune('a'+i))+`: weather(city: "`+city+`") { city }`)
+	}
+	out = query("{ " + strings.Join(fields, " ") + " }")
+	if !strings.Contains(toJSON(out["errors"]), graph.ErrTooComplex.Error()) {
+		t.Fatalf("❌ Expected complexity error, got %v", out["errors"])
+	}
+
+	// Глубина: me.favoriteCities.weather.city — 4 уровня при лимите 3
+	out = query(`{ me { favoriteCities { weather { city } } } }`)
+	if out["errors"] == nil || out["data"] != nil {
+		t.Fatalf("❌ Expected depth limit error, got %v", out)
+	}
+}
+
+func toJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
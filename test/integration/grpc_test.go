@@ -0,0 +1,81 @@
+// test/integration/grpc_test.go
+package integration
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"service-info/internal/grpcserver"
+	"service-info/internal/models"
+	pb "service-info/internal/pb/serviceinfov1"
+	"service-info/internal/stream"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPC_AuthAndWatchUpdates(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+	rdb.Del(context.Background(), stream.LogKey)
+	rdb.Set(context.Background(), "user:4242", "1", time.Minute)
+	defer rdb.Del(context.Background(), "user:4242")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := stream.NewHub(rdb, 8)
+	go hub.Run(ctx)
+	time.Sleep(200 * time.Millisecond)
+
+	listener := bufconn.Listen(1 << 20)
+	srv := grpcserver.NewServer(nil, nil, nil, nil, nil, nil, hub).Register(rdb)
+	go srv.Serve(listener)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("❌ gRPC dial failed: %v", err)
+	}
+	defer conn.Close()
+	client := pb.NewServiceInfoClient(conn)
+
+	// Без x-user-id и с незарегистрированным пользователем — Unauthenticated
+	if _, err := client.GetWeather(ctx, &pb.GetWeatherRequest{City: "Moscow"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("❌ Expected Unauthenticated without metadata, got %v", err)
+	}
+	stranger := metadata.AppendToOutgoingContext(ctx, "x-user-id", "999999999")
+	if _, err := client.GetWeather(stranger, &pb.GetWeatherRequest{City: "Moscow"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("❌ Expected Unauthenticated for unknown user, got %v", err)
+	}
+
+	authed := metadata.AppendToOutgoingContext(ctx, "x-user-id", "4242")
+	watch, err := client.WatchUpdates(authed, &pb.WatchUpdatesRequest{Weather: []string{"Moscow"}})
+	if err != nil {
+		t.Fatalf("❌ WatchUpdates failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	stream.NewPublisher(rdb).Publish(ctx, "weather:moscow", models.Weather{City: "Moscow", Temp: -3})
+
+	update, err := watch.Recv()
+	if err != nil {
+		t.Fatalf("❌ WatchUpdates Recv failed: %v", err)
+	}
+	if update.GetKey() != "weather:moscow" || update.GetId() == "" || len(update.GetData()) == 0 {
+		t.Fatalf("❌ Unexpected update: %+v", update)
+	}
+}
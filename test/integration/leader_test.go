@@ -0,0 +1,188 @@
+// test/integration/leader_test.go
+package integration
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service-info/internal/cron"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// leaderJob отмечает, выполняется ли задача на реплике
+type leaderJob struct {
+	running *atomic.Bool
+	starts  *atomic.Int32
+}
+
+func (j leaderJob) Start(ctx context.Context) {
+	j.starts.Add(1)
+	j.running.Store(true)
+	<-ctx.Done()
+	j.running.Store(false)
+}
+
+func newLeaderJob() leaderJob {
+	return leaderJob{running: &atomic.Bool{}, starts: &atomic.Int32{}}
+}
+
+func waitFor(t *testing.T, what string, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("❌ Timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestLeaderElector_AcquireRenewTakeoverRelease(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	const key = "cron:leader:test"
+	rdb.Del(ctx, key, key+":term")
+	defer rdb.Del(ctx, key, key+":term")
+
+	ttl := 600 * time.Millisecond
+	jobA, jobB := newLeaderJob(), newLeaderJob()
+
+	ctxA, cancelA := context.WithCancel(ctx)
+	defer cancelA()
+	go cron.NewLeaderElector(rdb, key, ttl).Run(ctxA, jobA)
+	waitFor(t, "replica A to lead", 2*time.Second, jobA.running.Load)
+
+	ctxB, cancelB := context.WithCancel(ctx)
+	defer cancelB()
+	go cron.NewLeaderElector(rdb, key, ttl).Run(ctxB, jobB)
+
+	// Несколько ttl подряд A продлевает аренду, B ждёт
+	time.Sleep(3 * ttl)
+	if !jobA.running.Load() || jobB.running.Load() {
+		t.Fatalf("❌ Expected only A to lead after renewals (A=%v, B=%v)", jobA.running.Load(), jobB.running.Load())
+	}
+
+	// Аренду забрали в обход A: A останавливает задачи, не трогая чужую аренду
+	rdb.Set(ctx, key, "intruder:999", ttl)
+	waitFor(t, "replica A to step down", 2*time.Second, func() bool { return !jobA.running.Load() })
+	if val := rdb.Get(ctx, key).Val(); val != "intruder:999" && !jobB.running.Load() {
+		t.Errorf("❌ A released a lease it no longer held: %q", val)
+	}
+
+	// После истечения чужой аренды лидером становится одна из реплик
+	waitFor(t, "a new leader", 3*time.Second, func() bool { return jobA.running.Load() || jobB.running.Load() })
+	time.Sleep(ttl)
+	if jobA.running.Load() == jobB.running.Load() {
+		t.Fatalf("❌ Expected exactly one leader (A=%v, B=%v)", jobA.running.Load(), jobB.running.Load())
+	}
+
+	// Остановленный лидер сразу отдаёт аренду, не дожидаясь ttl
+	leader, follower, cancelLeader := jobA, jobB, cancelA
+	if jobB.running.Load() {
+		leader, follower, cancelLeader = jobB, jobA, cancelB
+	}
+	cancelLeader()
+	waitFor(t, "leader to stop", time.Second, func() bool { return !leader.running.Load() })
+	waitFor(t, "follower to take over", ttl, follower.running.Load)
+}
+
+// redisProxy пропускает соединения к Redis, пока его не оборвут через cut
+type redisProxy struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+	cut   bool
+}
+
+func newRedisProxy(t *testing.T, target string) *redisProxy {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("❌ Listen failed: %v", err)
+	}
+	p := &redisProxy{ln: ln}
+	t.Cleanup(func() { ln.Close(); p.Cut() })
+
+	go func() {
+		for {
+			client, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			p.mu.Lock()
+			if p.cut {
+				p.mu.Unlock()
+				client.Close()
+				continue
+			}
+			server, err := net.Dial("tcp", target)
+			if err != nil {
+				p.mu.Unlock()
+				client.Close()
+				continue
+			}
+			p.conns = append(p.conns, client, server)
+			p.mu.Unlock()
+			go io.Copy(server, client)
+			go io.Copy(client, server)
+		}
+	}()
+	return p
+}
+
+func (p *redisProxy) Addr() string {
+	return p.ln.Addr().String()
+}
+
+// Cut рвёт текущие соединения и отклоняет новые
+func (p *redisProxy) Cut() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cut = true
+	for _, c := range p.conns {
+		c.Close()
+	}
+	p.conns = nil
+}
+
+func TestLeaderElector_StepsDownBeforeLeaseExpires(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	const key = "cron:leader:stepdown:test"
+	rdb.Del(ctx, key, key+":term")
+	defer rdb.Del(ctx, key, key+":term")
+
+	proxy := newRedisProxy(t, "localhost:6379")
+	viaProxy := redis.NewClient(&redis.Options{Addr: proxy.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer viaProxy.Close()
+
+	ttl := 900 * time.Millisecond
+	job := newLeaderJob()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go cron.NewLeaderElector(viaProxy, key, ttl).Run(runCtx, job)
+	waitFor(t, "replica to lead", 2*time.Second, job.running.Load)
+
+	// Redis пропал для лидера: задачи должны встать, пока аренда ещё жива
+	proxy.Cut()
+	waitFor(t, "leader to step down", 2*ttl, func() bool { return !job.running.Load() })
+	if pttl := rdb.PTTL(ctx, key).Val(); pttl <= 0 {
+		t.Errorf("❌ Leader stepped down only after its lease expired (PTTL %v)", pttl)
+	}
+}
@@ -0,0 +1,278 @@
+// test/integration/notify_test.go
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"service-info/internal/models"
+	"service-info/internal/notify"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+)
+
+func TestDispatcher_TelegramAndWebhook(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+
+	// Telegram-заглушка: первый вызов падает с 502, второй успешен — проверяем ретрай
+	var telegramCalls atomic.Int32
+	var telegramText atomic.Value
+	telegram := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottest-token/sendMessage" {
+			t.Errorf("❌ Unexpected Telegram path %s", r.URL.Path)
+		}
+		if telegramCalls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		telegramText.Store(body["text"])
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+	}))
+	defer telegram.Close()
+
+	var signatureOK atomic.Bool
+	var secret atomic.Value
+	secret.Store("")
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := "sha256=" + notify.Sign(secret.Load().(string), r.Header.Get(notify.TimestampHeader), body)
+		signatureOK.Store(r.Header.Get(notify.SignatureHeader) == want)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	// Заглушка слушает на 127.0.0.1, поэтому запрет внутренних адресов снят
+	localWebhook := notify.NewWebhook()
+	localWebhook.AllowPrivate = true
+
+	repo := repositories.NewNotificationRepository(db)
+	dispatcher := notify.NewDispatcher(repo, 3, 10*time.Millisecond,
+		notify.NewTelegram(telegram.URL, "test-token"),
+		localWebhook,
+	)
+	notificationService := services.NewNotificationService(repo, dispatcher)
+
+	if created, err := notificationService.AddChannel(ctx, models.NotificationChannel{UserID: 1, Channel: "telegram", Destination: "123456", Secret: "ignored"}); err != nil {
+		t.Fatalf("❌ AddChannel(telegram) failed: %v", err)
+	} else if created.Secret != "" {
+		t.Errorf("❌ Expected no secret for telegram, got %q", created.Secret)
+	}
+	// Секрет webhook выдаётся при создании канала и только тогда
+	created, err := notificationService.AddChannel(ctx, models.NotificationChannel{UserID: 1, Channel: "webhook", Destination: webhook.URL + "/hook"})
+	if err != nil {
+		t.Fatalf("❌ AddChannel(webhook) failed: %v", err)
+	}
+	if len(created.Secret) != 64 {
+		t.Fatalf("❌ Expected a generated webhook secret, got %q", created.Secret)
+	}
+	secret.Store(created.Secret)
+	listed, err := notificationService.ListChannels(ctx, 1)
+	if err != nil {
+		t.Fatalf("❌ ListChannels failed: %v", err)
+	}
+	for _, ch := range listed {
+		if ch.Secret != "" {
+			t.Errorf("❌ Secret of channel %d leaked in ListChannels", ch.ID)
+		}
+	}
+	other, err := notificationService.AddChannel(ctx, models.NotificationChannel{UserID: 2, Channel: "webhook", Destination: webhook.URL + "/hook"})
+	if err != nil || other.Secret == "" || other.Secret == created.Secret {
+		t.Errorf("❌ Expected a separate secret per channel, got %+v (%v)", other, err)
+	}
+	if _, err := notificationService.AddChannel(ctx, models.NotificationChannel{UserID: 1, Channel: "email", Destination: "a@b.c"}); err == nil {
+		t.Error("❌ Expected email channel to be rejected when SMTP is not configured")
+	}
+
+	n := models.Notification{ID: "alert:1", UserID: 1, Kind: models.NotificationExchangeAlert, Message: "USD/RUB is above 100: 101"}
+	if delivered := dispatcher.Deliver(ctx, n); delivered != 2 {
+		t.Fatalf("❌ Expected 2 deliveries, got %d", delivered)
+	}
+	if !strings.Contains(telegramText.Load().(string), "USD/RUB") {
+		t.Errorf("❌ Unexpected Telegram text %q", telegramText.Load())
+	}
+	if !signatureOK.Load() {
+		t.Error("❌ Webhook signature mismatch")
+	}
+
+	// Повторная доставка того же уведомления не отправляет его снова
+	dispatcher.Deliver(ctx, n)
+	if calls := telegramCalls.Load(); calls != 2 {
+		t.Errorf("❌ Expected 2 Telegram calls (one retry), got %d", calls)
+	}
+
+	deliveries, err := notificationService.ListDeliveries(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("❌ ListDeliveries failed: %v", err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("❌ Expected 2 delivery records, got %d", len(deliveries))
+	}
+	for _, d := range deliveries {
+		if d.Status != models.DeliveryDelivered {
+			t.Errorf("❌ Delivery %+v not delivered", d)
+		}
+		if d.Channel == "telegram" && d.Attempts != 2 {
+			t.Errorf("❌ Expected 2 Telegram attempts, got %d", d.Attempts)
+		}
+	}
+}
+
+func TestNotificationService_RejectsInternalWebhooks(t *testing.T) {
+	// До репозитория дело не доходит — адрес отклоняется при проверке, БД не нужна
+	dispatcher := notify.NewDispatcher(nil, 1, time.Millisecond, notify.NewWebhook())
+	notificationService := services.NewNotificationService(nil, dispatcher)
+
+	for _, destination := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:6379/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5:5432/",
+		"http://[::1]/hook",
+		"http://0.0.0.0/",
+	} {
+		_, err := notificationService.AddChannel(context.Background(), models.NotificationChannel{UserID: 1, Channel: "webhook", Destination: destination})
+		if !errors.Is(err, services.ErrInvalidChannel) {
+			t.Errorf("❌ Expected %s to be rejected, got %v", destination, err)
+		}
+	}
+
+	// Даже если адрес миновал проверку (DNS rebinding), соединение режется при отправке
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("❌ Internal server must not be reached")
+	}))
+	defer internal.Close()
+
+	webhook := notify.NewWebhook()
+	target := models.NotificationChannel{Channel: "webhook", Destination: internal.URL + "/hook", Secret: "secret"}
+	err := webhook.Send(context.Background(), target, models.Notification{ID: "n1", Message: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "loopback") {
+		t.Errorf("❌ Expected dial to internal address to fail, got %v", err)
+	}
+}
+
+// TestEmail_SMTPStub отправляет письмо в минимальную SMTP-заглушку
+func TestEmail_SMTPStub(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("❌ listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 stub ESMTP")
+
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 stub")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	email := notify.NewEmail(ln.Addr().String(), "bot@example.com", "", "")
+	err = email.Send(context.Background(), models.NotificationChannel{Channel: "email", Destination: "user@example.com"}, models.Notification{
+		Kind:    models.NotificationWeatherAlert,
+		Message: "moscow: temp is below -20 (now -25)",
+	})
+	if err != nil {
+		t.Fatalf("❌ Email send failed: %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if !strings.Contains(msg, "To: user@example.com") || !strings.Contains(msg, "below -20") {
+			t.Errorf("❌ Unexpected email:\n%s", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("❌ SMTP stub received nothing")
+	}
+}
+
+// TestEmail_SMTPTimeout — SMTP-сервер принял соединение и молчит
+func TestEmail_SMTPTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("❌ listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	email := notify.NewEmail(ln.Addr().String(), "bot@example.com", "", "")
+	email.Timeout = 200 * time.Millisecond
+	target := models.NotificationChannel{Channel: "email", Destination: "user@example.com"}
+
+	start := time.Now()
+	if err := email.Send(context.Background(), target, models.Notification{Message: "hi"}); err == nil {
+		t.Fatal("❌ Expected send to a silent SMTP server to fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("❌ Expected send to give up after Timeout, took %v", elapsed)
+	}
+
+	// Отмена контекста обрывает ожидание раньше Timeout
+	email.Timeout = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if err := email.Send(ctx, target, models.Notification{Message: "hi"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("❌ Expected context deadline, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("❌ Expected cancellation to stop the send, took %v", elapsed)
+	}
+}
@@ -0,0 +1,164 @@
+// test/integration/openapi_test.go
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"service-info/internal/api"
+	"service-info/internal/apierror"
+	"service-info/internal/bootstrap"
+	"service-info/internal/commands"
+	"service-info/internal/graph"
+	"service-info/internal/handlers"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/openapi"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// Каждый маршрут роутера должен быть описан в спецификации
+func TestOpenAPI_AllRoutesDocumented(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("❌ OpenAPI spec is invalid: %v", err)
+	}
+
+	router := bootstrap.InitRoutes(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	err = chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimPrefix(route, "/v1")
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		item := doc.Paths.Find(route)
+		if item == nil || item.GetOperation(method) == nil {
+			t.Errorf("❌ %s %s is not documented in openapi.yaml", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("❌ chi.Walk failed: %v", err)
+	}
+}
+
+// Ответы хэндлеров, которым не нужны Postgres и Kafka, сверяются со спецификацией
+func TestOpenAPI_ResponsesMatchSpec(t *testing.T) {
+	validator, err := openapi.NewValidator(func(r *http.Request, err error) {
+		t.Errorf("❌ %s %s does not match spec: %v", r.Method, r.URL.Path, err)
+	})
+	if err != nil {
+		t.Fatalf("❌ NewValidator failed: %v", err)
+	}
+
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	fetcher := &testutils.StubWeatherFetcher{
+		Errors: map[string]error{
+			"Nowhere": fmt.Errorf("%w: city %q: No matching location found.", api.ErrNotFound, "Nowhere"),
+		},
+	}
+	weather := services.NewCacheService[models.Weather](rdb, nil, fetcher)
+	weatherHandler := handlers.NewWeatherHandler(weather, nil, nil)
+	graphqlHandler := handlers.NewGraphQLHandler(graph.NewSchema(weather, nil, nil, nil, nil, graph.Limits{MaxDepth: 6, MaxComplexity: 50}))
+	registry, err := commands.NewRegistry(services.WeatherCommand, services.ExchangeCommand)
+	if err != nil {
+		t.Fatalf("❌ NewRegistry failed: %v", err)
+	}
+	adminHandler := handlers.NewAdminHandler(nil, registry)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, validator.Middleware)
+	r.Get("/openapi.json", openapi.ServeSpec)
+	r.Route("/v1", func(r chi.Router) {
+		r.Use(middleware.APIVersion("v1"))
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, int64(42))))
+			})
+		})
+		r.Get("/admin/commands", adminHandler.ListCommands)
+		r.Post("/admin", adminHandler.CreatePopular)
+		r.Get("/weather", weatherHandler.GetWeather)
+		r.Post("/weather/batch", weatherHandler.GetWeatherBatch)
+		r.Post("/graphql", graphqlHandler.Query)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	cases := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"GET", "/openapi.json", "", 200},
+		{"GET", "/v1/admin/commands", "", 200},
+		{"POST", "/v1/admin", `{"text":"/help"}`, 200},
+		{"POST", "/v1/admin", `{"text":"/unknown Moscow"}`, 400},
+		{"GET", "/v1/weather?city=OpenAPITown", "", 200},
+		{"GET", "/v1/weather?city=Nowhere", "", 404},
+		{"POST", "/v1/weather/batch", `{"cities":["OpenAPITown","Nowhere"]}`, 200},
+		{"GET", "/v1/weather?city=OpenAPITown&lang=en&units=imperial", "", 200},
+		{"POST", "/v1/weather/batch?units=imperial", `{"cities":["OpenAPITown"]}`, 200},
+		{"POST", "/v1/graphql", `{"query":"{ weather(city: \"OpenAPITown\") { city } }"}`, 200},
+		// Запросы, нарушающие спецификацию, отсекаются валидатором
+		{"POST", "/v1/weather/batch", `{"cities":[]}`, 400},
+		{"POST", "/v1/graphql", `{"variables":{}}`, 400},
+		{"GET", "/v1/weather?city=OpenAPITown&units=kelvin", "", 400},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, srv.URL+c.path, bytes.NewBufferString(c.body))
+		if c.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("X-User-ID", "42")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("❌ %s %s failed: %v", c.method, c.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.status {
+			t.Errorf("❌ %s %s: expected %d, got %d", c.method, c.path, c.status, resp.StatusCode)
+		}
+	}
+
+	// Неизвестный город — 404 с кодом и тем же request_id, что в заголовке
+	req, _ := http.NewRequest("GET", srv.URL+"/v1/weather?city=Nowhere", nil)
+	req.Header.Set("X-Request-ID", "test-request-1")
+	notFound, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("❌ GET /v1/weather failed: %v", err)
+	}
+	defer notFound.Body.Close()
+	var apiErr apierror.Error
+	if err := json.NewDecoder(notFound.Body).Decode(&apiErr); err != nil {
+		t.Fatalf("❌ Error envelope decode failed: %v", err)
+	}
+	if apiErr.Code != apierror.CodeNotFound || apiErr.RequestID != "test-request-1" || apiErr.Details["city"] != "Nowhere" {
+		t.Errorf("❌ Unexpected error envelope: %+v", apiErr)
+	}
+	if notFound.Header.Get("X-Request-ID") != "test-request-1" {
+		t.Errorf("❌ Expected X-Request-ID echoed, got %q", notFound.Header.Get("X-Request-ID"))
+	}
+
+	// Спецификация отдаётся корректным JSON
+	resp, err := http.Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("❌ GET /openapi.json failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var spec map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil || spec["openapi"] != "3.0.3" {
+		t.Fatalf("❌ Unexpected /openapi.json: %v %v", err, spec["openapi"])
+	}
+}
@@ -8,9 +8,13 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"service-info/internal/apierror"
+	"service-info/internal/commands"
+	"service-info/internal/config"
 	"service-info/internal/cron"
 	"service-info/internal/handlers"
 	"service-info/internal/kafka"
@@ -68,15 +72,33 @@ func TestPopularPublisher_KafkaToRedis(t *testing.T) {
 
 	adminRepo := repositories.NewAdminRepository(db)
 	adminService := services.NewAdminService(adminRepo)
-	adminHandler := handlers.NewAdminHandler(adminService)
+	registry, err := commands.NewRegistry(
+		services.WeatherCommand,
+		services.ExchangeCommand,
+	)
+	if err != nil {
+		t.Fatalf("❌ NewRegistry failed: %v", err)
+	}
+	adminHandler := handlers.NewAdminHandler(adminService, registry)
+	popularService := services.NewPopularService(
+		rdb,
+		adminRepo,
+		registry,
+		func(string) models.PopularityPolicy {
+			return models.PopularityPolicy{Window: 24 * time.Hour, TopN: 5, MinCount: 1}
+		},
+		time.Minute,
+	)
+	popularHandler := handlers.NewPopularHandler(popularService)
 
 	router := http.NewServeMux()
-	router.HandleFunc("POST /admin/popular", adminHandler.CreatePopular)
-	srv := httptest.NewServer(router)
+	router.HandleFunc("POST /admin", adminHandler.CreatePopular)
+	router.HandleFunc("GET /popular", popularHandler.GetPopular)
+	srv := httptest.NewServer(testutils.WithSpec(t, router))
 	defer srv.Close()
 
 	payload := `{"text":"/weather Moscow"}`
-	req, _ := http.NewRequest("POST", srv.URL+"/admin/popular", bytes.NewReader([]byte(payload)))
+	req, _ := http.NewRequest("POST", srv.URL+"/admin", bytes.NewReader([]byte(payload)))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
@@ -90,10 +112,12 @@ func TestPopularPublisher_KafkaToRedis(t *testing.T) {
 	log.Println("✅ '/weather Moscow' saved to DB")
 
 	publisher := cron.NewPopularPublisher(
-		adminRepo,
+		popularService,
 		producer,
+		nil,
 		"popular-requests",
 		1*time.Minute,
+		cron.RefreshTiming{},
 	)
 
 	if err := publisher.RunOnce(context.Background()); err != nil {
@@ -124,5 +148,187 @@ func TestPopularPublisher_KafkaToRedis(t *testing.T) {
 		log.Printf("✅ Weather for %q received", fromRedis.City)
 	}
 
+	popularResp, err := http.Get(srv.URL + "/popular?type=weather&window=24h")
+	if err != nil {
+		t.Fatalf("❌ GET /popular failed: %v", err)
+	}
+	defer popularResp.Body.Close()
+
+	var popular struct {
+		Items []models.PopularItem `json:"items"`
+	}
+	if err := json.NewDecoder(popularResp.Body).Decode(&popular); err != nil {
+		t.Fatalf("❌ /popular JSON decode failed: %v", err)
+	}
+	if len(popular.Items) == 0 || popular.Items[0].Args["city"] != "moscow" {
+		t.Fatalf("❌ Expected moscow on top of /popular, got %+v", popular.Items)
+	}
+	if popular.Items[0].Count != 1 || len(popular.Items[0].Value) == 0 {
+		t.Errorf("❌ Expected count=1 and cached value, got %+v", popular.Items[0])
+	}
+
 	log.Println("🎉 TEST PASSED: PopularPublisher → Kafka → Multiplexer → Worker → Redis")
 }
+
+func TestRefreshTiming_Due(t *testing.T) {
+	timing := cron.RefreshTiming{MinLead: 10 * time.Second, MaxLead: 80 * time.Second}
+
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		score    float64
+		maxScore float64
+		want     bool
+	}{
+		{"missing key refreshed at once", -2, 1, 10, true},
+		{"key without expiry never refreshed", -1, 10, 10, false},
+		{"hot key refreshed MaxLead early", 60 * time.Second, 10, 10, true},
+		{"hot key at MaxLead", 80 * time.Second, 10, 10, true},
+		{"fresh hot key skipped", 100 * time.Second, 10, 10, false},
+		{"cold key not refreshed at hot lead", 60 * time.Second, 0, 10, false},
+		{"cold key refreshed MinLead early", 5 * time.Second, 0, 10, true},
+		{"warm key lead in between", 40 * time.Second, 5, 10, true},
+		{"warm key still fresh", 50 * time.Second, 5, 10, false},
+		{"no scores treated as hot", 60 * time.Second, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := timing.Due(tt.ttl, tt.score, tt.maxScore); got != tt.want {
+				t.Errorf("❌ Due(%v, %v, %v) = %v, want %v", tt.ttl, tt.score, tt.maxScore, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopularPublisher_RefreshesOnlyDueKeys(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	adminRepo := repositories.NewAdminRepository(db)
+	requests := map[string]int{"hottown": 4, "coldtown": 1, "freshtown": 4, "eternaltown": 4, "missingtown": 1}
+	for city, n := range requests {
+		for i := 0; i < n; i++ {
+			if _, err := adminRepo.Save(ctx, models.Task{Title: "weather", Args: models.TaskArgs{"city": city}, CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("❌ Save failed: %v", err)
+			}
+		}
+	}
+
+	registry, err := commands.NewRegistry(services.WeatherCommand, services.ExchangeCommand)
+	if err != nil {
+		t.Fatalf("❌ NewRegistry failed: %v", err)
+	}
+	popularService := services.NewPopularService(
+		rdb,
+		adminRepo,
+		registry,
+		func(string) models.PopularityPolicy {
+			return models.PopularityPolicy{Window: 24 * time.Hour, TopN: 10, MinCount: 1}
+		},
+		time.Minute,
+	)
+
+	key := func(city string) string {
+		return popularService.CacheKey(models.PopularRequest{Type: "weather", Args: models.TaskArgs{"city": city}})
+	}
+	var cleanup []string
+	for city := range requests {
+		cleanup = append(cleanup, key(city), "refresh:pending:"+key(city))
+	}
+	rdb.Del(ctx, cleanup...)
+	defer rdb.Del(ctx, cleanup...)
+
+	// hottown: 60s < MaxLead; coldtown: те же 60s, но lead у холодного ключа меньше;
+	// freshtown ещё долго живёт; eternaltown без TTL; missingtown в кэше нет
+	rdb.Set(ctx, key("hottown"), "{}", 60*time.Second)
+	rdb.Set(ctx, key("coldtown"), "{}", 60*time.Second)
+	rdb.Set(ctx, key("freshtown"), "{}", 10*time.Minute)
+	rdb.Set(ctx, key("eternaltown"), "{}", 0)
+
+	producer := &capturingSyncProducer{}
+	publisher := cron.NewPopularPublisher(
+		popularService,
+		producer,
+		nil,
+		"popular-requests",
+		time.Minute,
+		cron.RefreshTiming{MinLead: 10 * time.Second, MaxLead: 80 * time.Second},
+	)
+
+	if err := publisher.RunOnce(ctx); err != nil {
+		t.Fatalf("❌ RunOnce failed: %v", err)
+	}
+	published := map[string]bool{}
+	for _, k := range producer.keys {
+		published[k] = true
+	}
+	if len(producer.keys) != 2 || !published[key("hottown")] || !published[key("missingtown")] {
+		t.Fatalf("❌ Expected only hottown and missingtown refreshed, got %v", producer.keys)
+	}
+
+	// Пока команда в очереди, refresh:pending не даёт отправить её ещё раз
+	if err := publisher.RunOnce(ctx); err != nil {
+		t.Fatalf("❌ RunOnce failed: %v", err)
+	}
+	if len(producer.keys) != 2 {
+		t.Errorf("❌ Expected pending keys not to be republished, got %v", producer.keys)
+	}
+}
+
+func TestConfig_PopularityPolicyClamped(t *testing.T) {
+	t.Setenv("POPULAR_WEATHER_WINDOW", "-1h")
+	t.Setenv("POPULAR_WEATHER_TOP_N", "0")
+	t.Setenv("POPULAR_WEATHER_MIN_COUNT", "-3")
+	t.Setenv("POPULAR_WEATHER_HALF_LIFE", "-10m")
+	t.Setenv("POPULAR_EXCHANGE_TOP_N", "20")
+	t.Setenv("POPULAR_EXCHANGE_HALF_LIFE", "6h")
+
+	cfg := &config.Config{}
+
+	want := models.PopularityPolicy{Window: 24 * time.Hour, TopN: 1, MinCount: 1}
+	if got := cfg.PopularityPolicy("weather"); got != want {
+		t.Errorf("❌ Expected invalid weather policy clamped to %+v, got %+v", want, got)
+	}
+
+	want = models.PopularityPolicy{Window: 24 * time.Hour, TopN: 20, MinCount: 1, HalfLife: 6 * time.Hour}
+	if got := cfg.PopularityPolicy("exchange"); got != want {
+		t.Errorf("❌ Expected valid exchange policy kept as %+v, got %+v", want, got)
+	}
+}
+
+// Неизвестные окно и тип отсекаются до Redis с перечнем допустимых значений;
+// окно — перечисление спецификации, тип — реестр команд
+func TestPopularHandler_RejectsUnknownParams(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	popularService := services.NewPopularService(rdb, nil, newTestRegistry(t), nil, time.Minute)
+	srv := httptest.NewServer(testutils.WithSpec(t, http.HandlerFunc(handlers.NewPopularHandler(popularService).GetPopular)))
+	defer srv.Close()
+
+	cases := []struct {
+		query, message string
+	}{
+		{"type=weather&window=30d", "window"},
+		{"type=forecast", "query parameter 'type' must be one of: exchange, weather"},
+		{"type=forecast&window=1h", "query parameter 'type' must be one of: exchange, weather"},
+	}
+	for _, c := range cases {
+		resp, err := http.Get(srv.URL + "/popular?" + c.query)
+		if err != nil {
+			t.Fatalf("❌ GET /popular?%s failed: %v", c.query, err)
+		}
+		var apiErr apierror.Error
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || apiErr.Code != apierror.CodeInvalidArgument || !strings.Contains(apiErr.Message, c.message) {
+			t.Errorf("❌ %s: expected 400 %q, got %d %+v", c.query, c.message, resp.StatusCode, apiErr)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// test/integration/preferences_test.go
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"service-info/internal/api"
+	"service-info/internal/handlers"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+// stubExchangeFetcher отдаёт курс из rates по паре BASE_TARGET, для остальных — ошибку
+type stubExchangeFetcher struct {
+	rates map[string]float64
+}
+
+func (stubExchangeFetcher) CacheKey(params ...string) string {
+	return services.ExchangeFetcher{}.CacheKey(params...)
+}
+
+func (f stubExchangeFetcher) Fetch(ctx context.Context, params ...string) (*models.ExchangeRate, error) {
+	rate, ok := f.rates[params[0]+"_"+params[1]]
+	if !ok {
+		return nil, fmt.Errorf("%w: pair %s/%s", api.ErrNotFound, params[0], params[1])
+	}
+	return &models.ExchangeRate{Base: params[0], Target: params[1], Rate: rate, Updated: time.Now().Format(time.RFC3339)}, nil
+}
+
+func TestPreferences_NormalizeAndCache(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	const userID = 9001
+	key := fmt.Sprintf("prefs:%d", userID)
+	rdb.Del(ctx, key)
+	defer rdb.Del(ctx, key)
+
+	prefs := services.NewPreferencesService(repositories.NewPreferencesRepository(db), rdb, nil, nil, time.Minute)
+
+	tooMany := make([]string, 11)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("City%d", i)
+	}
+	invalid := map[string]models.UserPreferences{
+		"11 favorite cities": {UserID: userID, FavoriteCities: tooMany},
+		"11 favorite pairs":  {UserID: userID, FavoritePairs: make([]models.CurrencyPair, 11)},
+		"empty city":         {UserID: userID, FavoriteCities: []string{"Kazan", "  "}},
+		"2-letter currency":  {UserID: userID, FavoritePairs: []models.CurrencyPair{{Base: "US", Target: "EUR"}}},
+		"non-latin currency": {UserID: userID, FavoritePairs: []models.CurrencyPair{{Base: "USD", Target: "ЕВР"}}},
+	}
+	for name, p := range invalid {
+		if _, err := prefs.Save(ctx, p); !errors.Is(err, services.ErrInvalidPreferences) {
+			t.Errorf("❌ %s: expected ErrInvalidPreferences, got %v", name, err)
+		}
+	}
+	if n, _ := rdb.Exists(ctx, key).Result(); n != 0 {
+		t.Fatalf("❌ Rejected preferences must not be cached")
+	}
+
+	// Ровно 10 — ещё можно; дубликаты городов и пар схлопываются без учёта регистра
+	saved, err := prefs.Save(ctx, models.UserPreferences{
+		UserID:         userID,
+		DefaultCity:    "  Moscow ",
+		FavoriteCities: append([]string{" Kazan", "kazan"}, tooMany[:8]...),
+		FavoritePairs:  []models.CurrencyPair{{Base: " usd", Target: "eur "}, {Base: "USD", Target: "EUR"}, {Base: "Usd", Target: "eUR"}},
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	if saved.DefaultCity != "Moscow" || len(saved.FavoriteCities) != 9 || saved.FavoriteCities[0] != "Kazan" {
+		t.Errorf("❌ Expected normalized cities, got %+v", saved)
+	}
+	if len(saved.FavoritePairs) != 1 || saved.FavoritePairs[0] != (models.CurrencyPair{Base: "USD", Target: "EUR"}) {
+		t.Errorf("❌ Expected normalized pairs, got %+v", saved.FavoritePairs)
+	}
+
+	var cached models.UserPreferences
+	data, err := rdb.Get(ctx, key).Bytes()
+	if err != nil || json.Unmarshal(data, &cached) != nil || cached.DefaultCity != "Moscow" {
+		t.Fatalf("❌ Expected saved preferences in %s, got %s (%v)", key, data, err)
+	}
+
+	// Get читает из кэша, пока его не перезапишет Save
+	if _, err := db.ExecContext(ctx, `UPDATE user_preferences SET default_city = 'Omsk' WHERE user_id = $1`, userID); err != nil {
+		t.Fatalf("❌ UPDATE failed: %v", err)
+	}
+	if got, _ := prefs.Get(ctx, userID); got == nil || got.DefaultCity != "Moscow" {
+		t.Errorf("❌ Expected cached Moscow, got %+v", got)
+	}
+
+	if _, err := prefs.Save(ctx, models.UserPreferences{UserID: userID, DefaultCity: "Perm"}); err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	if got, _ := prefs.Get(ctx, userID); got == nil || got.DefaultCity != "Perm" || len(got.FavoriteCities) != 0 {
+		t.Errorf("❌ Expected cache replaced by Save, got %+v", got)
+	}
+
+	// Без кэша — из Postgres, и кэш заполняется снова
+	rdb.Del(ctx, key)
+	if got, _ := prefs.Get(ctx, userID); got == nil || got.DefaultCity != "Perm" {
+		t.Errorf("❌ Expected Perm from Postgres, got %+v", got)
+	}
+	if n, _ := rdb.Exists(ctx, key).Result(); n != 1 {
+		t.Errorf("❌ Expected %s cached again after a miss", key)
+	}
+}
+
+func TestMe_DefaultCityAndDashboard(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+
+	// Redis не нужен: промах кэша уходит в Postgres и fetcher
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	fetcher := &testutils.StubWeatherFetcher{
+		Weather: models.Weather{Temp: 5},
+		Errors: map[string]error{
+			"Atlantis": fmt.Errorf("%w: city %q: No matching location found.", api.ErrNotFound, "Atlantis"),
+		},
+	}
+	weather := services.NewCacheService[models.Weather](rdb, nil, fetcher)
+	exchange := services.NewCacheService[models.ExchangeRate](rdb, nil, stubExchangeFetcher{rates: map[string]float64{"USD_EUR": 0.9}})
+	prefs := services.NewPreferencesService(repositories.NewPreferencesRepository(db), rdb, weather, exchange, time.Minute)
+	rdb.Del(ctx, "prefs:7", "prefs:8")
+	defer rdb.Del(ctx, "prefs:7", "prefs:8")
+
+	weatherHandler := handlers.NewWeatherHandler(weather, nil, prefs)
+	meHandler := handlers.NewMeHandler(prefs)
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var userID int64
+			fmt.Sscan(r.Header.Get("X-Test-User"), &userID)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, userID)))
+		})
+	})
+	router.Get("/weather", weatherHandler.GetWeather)
+	router.Get("/me/preferences", meHandler.GetPreferences)
+	router.Put("/me/preferences", meHandler.PutPreferences)
+	router.Get("/me/dashboard", meHandler.GetDashboard)
+	srv := httptest.NewServer(testutils.WithSpec(t, router))
+	defer srv.Close()
+
+	do := func(method, path string, userID int64, body string, out any) int {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		req.Header.Set("X-Test-User", fmt.Sprint(userID))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("❌ %s %s failed: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		if out != nil {
+			json.NewDecoder(resp.Body).Decode(out)
+		}
+		return resp.StatusCode
+	}
+
+	if status := do("PUT", "/me/preferences", 7, `{"favorite_pairs":[{"base":"usd","target":"euro"}]}`, nil); status != http.StatusBadRequest {
+		t.Fatalf("❌ Expected 400 on invalid pair, got %d", status)
+	}
+	put := `{"favorite_cities":[" PrefsTown ","Atlantis"],"favorite_pairs":[{"base":"usd","target":"eur"},{"base":"usd","target":"xxx"}]}`
+	if status := do("PUT", "/me/preferences", 7, put, nil); status != http.StatusOK {
+		t.Fatalf("❌ Expected 200 on PUT /me/preferences, got %d", status)
+	}
+	var got models.UserPreferences
+	if status := do("GET", "/me/preferences", 7, "", &got); status != http.StatusOK || len(got.FavoriteCities) != 2 || got.FavoriteCities[0] != "PrefsTown" {
+		t.Fatalf("❌ Unexpected GET /me/preferences %d %+v", status, got)
+	}
+
+	// Без city /weather берёт первый избранный город, а без настроек — 400
+	var w models.Weather
+	if status := do("GET", "/weather", 7, "", &w); status != http.StatusOK || w.City != "PrefsTown" {
+		t.Errorf("❌ Expected weather for favorite PrefsTown, got %d %+v", status, w)
+	}
+	if status := do("GET", "/weather", 8, "", nil); status != http.StatusBadRequest {
+		t.Errorf("❌ Expected 400 without city and preferences, got %d", status)
+	}
+	if status := do("PUT", "/me/preferences", 8, `{"default_city":"Atlantis"}`, nil); status != http.StatusOK {
+		t.Fatalf("❌ Expected 200 on PUT /me/preferences, got %d", status)
+	}
+	if status := do("GET", "/weather", 8, "", nil); status != http.StatusNotFound {
+		t.Errorf("❌ Expected default city Atlantis to be used (404), got %d", status)
+	}
+
+	// Сбой одного города или пары не роняет весь дашборд
+	var dashboard models.Dashboard
+	if status := do("GET", "/me/dashboard", 7, "", &dashboard); status != http.StatusOK {
+		t.Fatalf("❌ Expected 200 on partial failures, got %d", status)
+	}
+	if item := dashboard.Weather["PrefsTown"]; item.Data == nil || item.Data.Temp != 5 || item.Error != "" {
+		t.Errorf("❌ Expected weather for PrefsTown, got %+v", item)
+	}
+	if item := dashboard.Weather["Atlantis"]; item.Data != nil || item.Error != "weather unavailable" {
+		t.Errorf("❌ Expected error for Atlantis, got %+v", item)
+	}
+	if item := dashboard.Exchange["USD_EUR"]; item.Data == nil || item.Data.Rate != 0.9 {
+		t.Errorf("❌ Expected USD/EUR rate, got %+v", item)
+	}
+	if item := dashboard.Exchange["USD_XXX"]; item.Data != nil || item.Error != "exchange rate unavailable" {
+		t.Errorf("❌ Expected error for USD/XXX, got %+v", item)
+	}
+	if len(dashboard.Weather) != 2 || len(dashboard.Exchange) != 2 {
+		t.Errorf("❌ Expected 2 cities and 2 pairs, got %+v", dashboard)
+	}
+
+	// Основной город попадает в дашборд первым, даже если его нет в избранном
+	dashboard = models.Dashboard{}
+	if status := do("GET", "/me/dashboard", 8, "", &dashboard); status != http.StatusOK || dashboard.DefaultCity != "Atlantis" || len(dashboard.Weather) != 1 {
+		t.Errorf("❌ Expected dashboard with default city only, got %d %+v", status, dashboard)
+	}
+}
@@ -0,0 +1,112 @@
+// test/integration/quota_test.go
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-info/internal/handlers"
+	"service-info/internal/quota"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestQuota_BudgetsAndReserve(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	now := time.Now().UTC()
+	dayKey := func(p string, at time.Time) string { return "quota:" + p + ":d:" + at.Format("20060102") }
+	monthKey := func(p string, at time.Time) string { return "quota:" + p + ":m:" + at.Format("200601") }
+	yesterday, lastMonth := now.AddDate(0, 0, -1), now.AddDate(0, -1, 0)
+	keys := []string{
+		dayKey(quota.ProviderWeatherAPI, now), monthKey(quota.ProviderWeatherAPI, now),
+		dayKey(quota.ProviderFreeCurrency, now), monthKey(quota.ProviderFreeCurrency, now),
+		dayKey(quota.ProviderWeatherAPI, yesterday), monthKey(quota.ProviderFreeCurrency, lastMonth),
+	}
+	rdb.Del(ctx, keys...)
+	defer rdb.Del(ctx, keys...)
+
+	// Счётчики прошлых суток и месяца не влияют на текущие
+	rdb.Set(ctx, dayKey(quota.ProviderWeatherAPI, yesterday), 1000, time.Hour)
+	rdb.Set(ctx, monthKey(quota.ProviderFreeCurrency, lastMonth), 1000, time.Hour)
+
+	tracker := quota.NewTracker(rdb, map[string]quota.Budget{
+		quota.ProviderWeatherAPI:   {Daily: 10},
+		quota.ProviderFreeCurrency: {Monthly: 5},
+	}, 20)
+
+	// Дневной бюджет 10 с резервом 20%: фоновые запросы останавливаются на 8
+	for i := 1; i <= 8; i++ {
+		if err := tracker.Acquire(ctx, quota.ProviderWeatherAPI, quota.PriorityBackground); err != nil {
+			t.Fatalf("❌ Background call %d rejected: %v", i, err)
+		}
+	}
+	if err := tracker.Acquire(ctx, quota.ProviderWeatherAPI, quota.PriorityBackground); !errors.Is(err, quota.ErrReserved) {
+		t.Fatalf("❌ Expected ErrReserved for background call 9, got %v", err)
+	}
+	if tracker.Allows(ctx, quota.ProviderWeatherAPI, quota.PriorityBackground) {
+		t.Error("❌ Allows(background) must be false once only the reserve is left")
+	}
+
+	// Пользовательские запросы расходуют резерв до нуля
+	for i := 9; i <= 10; i++ {
+		if err := tracker.Acquire(ctx, quota.ProviderWeatherAPI, quota.PriorityEssential); err != nil {
+			t.Fatalf("❌ Essential call %d rejected: %v", i, err)
+		}
+	}
+	if err := tracker.Acquire(ctx, quota.ProviderWeatherAPI, quota.PriorityEssential); !errors.Is(err, quota.ErrExhausted) {
+		t.Fatalf("❌ Expected ErrExhausted for essential call 11, got %v", err)
+	}
+
+	// Месячный бюджет без дневного
+	for i := 1; i <= 5; i++ {
+		if err := tracker.Acquire(ctx, quota.ProviderFreeCurrency, quota.PriorityEssential); err != nil {
+			t.Fatalf("❌ Monthly call %d rejected: %v", i, err)
+		}
+	}
+	if err := tracker.Acquire(ctx, quota.ProviderFreeCurrency, quota.PriorityEssential); !errors.Is(err, quota.ErrExhausted) {
+		t.Fatalf("❌ Expected monthly ErrExhausted, got %v", err)
+	}
+
+	if ttl := rdb.TTL(ctx, dayKey(quota.ProviderWeatherAPI, now)).Val(); ttl <= 0 || ttl > 48*time.Hour {
+		t.Errorf("❌ Daily counter TTL %v, want (0, 48h]", ttl)
+	}
+
+	// /admin/quota отдаёт те же числа
+	srv := httptest.NewServer(http.HandlerFunc(handlers.NewQuotaHandler(tracker).GetQuota))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("❌ GET /admin/quota failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Providers []quota.Usage `json:"providers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("❌ Decode failed: %v", err)
+	}
+
+	want := []quota.Usage{
+		{Provider: quota.ProviderWeatherAPI, DailyUsed: 10, DailyLimit: 10, DailyRemaining: 0, MonthlyUsed: 10, MonthlyRemaining: -1, ReservePercent: 20},
+		{Provider: quota.ProviderFreeCurrency, DailyUsed: 5, DailyRemaining: -1, MonthlyUsed: 5, MonthlyLimit: 5, MonthlyRemaining: 0, ReservePercent: 20},
+	}
+	if len(body.Providers) != len(want) {
+		t.Fatalf("❌ Expected %d providers, got %+v", len(want), body.Providers)
+	}
+	for i := range want {
+		if body.Providers[i] != want[i] {
+			t.Errorf("❌ Usage mismatch:\n got  %+v\n want %+v", body.Providers[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// test/integration/request_log_test.go
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"service-info/internal/handlers"
+	"service-info/internal/middleware"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// capturingProducer запоминает всё, что сервисы отправили бы в Kafka
+type capturingProducer struct {
+	mu   sync.Mutex
+	sent []interface{}
+}
+
+func (p *capturingProducer) PublishObjectAsync(key []byte, obj interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, obj)
+}
+
+func TestRequestLog_UserRequestsRankInPopularity(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+
+	// Redis не нужен: промах кэша уходит в fetcher
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	registry := newTestRegistry(t)
+	producer := &capturingProducer{}
+	recorder := services.NewRequestRecorder(producer, registry)
+	weather := services.NewCacheService[models.Weather](rdb, nil, &testutils.StubWeatherFetcher{})
+	handler := handlers.NewWeatherHandler(weather, recorder, nil)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.GetWeather(w, r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, int64(7))))
+	}))
+	defer srv.Close()
+
+	get := func(query string) int {
+		resp, err := http.Get(srv.URL + "/weather?" + query)
+		if err != nil {
+			t.Fatalf("❌ GET /weather?%s failed: %v", query, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	for i := 0; i < 3; i++ {
+		if status := get("city=Kazan"); status != http.StatusOK {
+			t.Fatalf("❌ Expected 200, got %d", status)
+		}
+	}
+	if status := get("city="); status != http.StatusBadRequest {
+		t.Fatalf("❌ Expected 400 without city, got %d", status)
+	}
+
+	// Синкер в проде получает эти события из Kafka; здесь сохраняем их напрямую
+	requestLog := repositories.NewRequestLogRepository(db)
+	producer.mu.Lock()
+	events := append([]interface{}(nil), producer.sent...)
+	producer.mu.Unlock()
+	if len(events) != 3 {
+		t.Fatalf("❌ Expected 3 request events (failed request not recorded), got %d", len(events))
+	}
+	for _, e := range events {
+		event, ok := e.(models.RequestEvent)
+		if !ok {
+			t.Fatalf("❌ Expected models.RequestEvent, got %T", e)
+		}
+		if event.Type != "weather" || event.Args["city"] != "kazan" || event.UserID != 7 {
+			t.Fatalf("❌ Unexpected request event %+v", event)
+		}
+		if err := requestLog.Save(ctx, event); err != nil {
+			t.Fatalf("❌ request_log Save failed: %v", err)
+		}
+	}
+
+	var rows int
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM request_log
+		WHERE type = 'weather' AND args->>'city' = 'kazan' AND user_id = 7
+	`).Scan(&rows); err != nil {
+		t.Fatalf("❌ request_log query failed: %v", err)
+	}
+	if rows != 3 {
+		t.Fatalf("❌ Expected 3 request_log rows, got %d", rows)
+	}
+
+	// Задачи из админки и запросы пользователей — одни и те же сигналы
+	adminRepo := repositories.NewAdminRepository(db)
+	for _, city := range []string{"moscow", "moscow", "sochi"} {
+		if _, err := adminRepo.Save(ctx, models.Task{Title: "weather", Args: models.TaskArgs{"city": city}, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("❌ Save failed: %v", err)
+		}
+	}
+	if err := requestLog.Save(ctx, models.RequestEvent{Type: "weather", Args: models.TaskArgs{"city": "sochi"}, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("❌ request_log Save failed: %v", err)
+	}
+
+	cmd, _ := registry.ByType("weather")
+	top, err := adminRepo.GetTopRequests(ctx, "weather", cmd.KeyArgs(), models.PopularityPolicy{Window: time.Hour, TopN: 10, MinCount: 1})
+	if err != nil {
+		t.Fatalf("❌ GetTopRequests failed: %v", err)
+	}
+	if len(top) != 3 {
+		t.Fatalf("❌ Expected 3 ranked cities, got %+v", top)
+	}
+	if top[0].Args["city"] != "kazan" || top[0].Count != 3 {
+		t.Errorf("❌ Expected kazan on top from user requests alone, got %+v", top[0])
+	}
+	// sochi: одна задача + один запрос — столько же, сколько у moscow
+	for _, req := range top[1:] {
+		if req.Count != 2 {
+			t.Errorf("❌ Expected %s counted from both sources, got %+v", req.Args["city"], req)
+		}
+	}
+}
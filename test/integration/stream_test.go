@@ -0,0 +1,148 @@
+// test/integration/stream_test.go
+package integration
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"service-info/internal/handlers"
+	"service-info/internal/models"
+	"service-info/internal/stream"
+
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+// readEvent читает одно SSE-событие (до пустой строки), пропуская служебные блоки
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	for {
+		event := make(map[string]string)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatalf("❌ SSE read failed: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			if line == "" {
+				break
+			}
+			if k, v, ok := strings.Cut(line, ": "); ok {
+				event[k] = v
+			}
+		}
+		if event["data"] != "" {
+			return event
+		}
+	}
+}
+
+func TestStream_SSEWithResume(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+	rdb.Del(context.Background(), stream.LogKey)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := stream.NewHub(rdb, 8)
+	go hub.Run(ctx)
+	time.Sleep(200 * time.Millisecond)
+
+	srv := httptest.NewServer(http.HandlerFunc(handlers.NewStreamHandler(hub).Stream))
+	defer srv.Close()
+
+	open := func(lastID string) (*http.Response, *bufio.Reader) {
+		req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/stream?weather=Moscow", nil)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("❌ GET /stream failed: %v", err)
+		}
+		return resp, bufio.NewReader(resp.Body)
+	}
+
+	resp, reader := open("")
+	time.Sleep(200 * time.Millisecond)
+
+	publisher := stream.NewPublisher(rdb)
+	publisher.Publish(ctx, "weather:moscow", models.Weather{City: "Moscow", Temp: -3})
+
+	first := readEvent(t, reader)
+	if first["event"] != "weather" || !strings.Contains(first["data"], `"Moscow"`) {
+		t.Fatalf("❌ Unexpected event %+v", first)
+	}
+	resp.Body.Close()
+
+	// Пока клиент отключён, приходят два обновления — после переподключения оба доставляются
+	publisher.Publish(ctx, "weather:moscow", models.Weather{City: "Moscow", Temp: -4})
+	publisher.Publish(ctx, "weather:london", models.Weather{City: "London", Temp: 12})
+	publisher.Publish(ctx, "weather:moscow", models.Weather{City: "Moscow", Temp: -5})
+
+	resp, reader = open(first["id"])
+	defer resp.Body.Close()
+
+	for _, want := range []string{"-4", "-5"} {
+		e := readEvent(t, reader)
+		if !strings.Contains(e["data"], `"temp_celsius":`+want) {
+			t.Fatalf("❌ Expected replayed temp %s, got %+v", want, e)
+		}
+	}
+}
+
+func TestStream_WebSocketSubscribe(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("❌ Redis недоступен: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hub := stream.NewHub(rdb, 8)
+	go hub.Run(ctx)
+	time.Sleep(200 * time.Millisecond)
+
+	srv := httptest.NewServer(http.HandlerFunc(handlers.NewWSHandler(hub).Serve))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("❌ WebSocket dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg map[string]interface{}
+	conn.WriteJSON(map[string]interface{}{"action": "subscribe", "exchange": []string{"USD_EUR"}})
+	if err := conn.ReadJSON(&msg); err != nil || msg["type"] != "subscribed" {
+		t.Fatalf("❌ Expected subscribed, got %v (%v)", msg, err)
+	}
+
+	stream.NewPublisher(rdb).Publish(ctx, "exchange:usd_eur", models.ExchangeRate{Base: "USD", Target: "EUR", Rate: 0.92})
+
+	msg = nil
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("❌ WebSocket read failed: %v", err)
+	}
+	if msg["type"] != "update" || msg["key"] != "exchange:usd_eur" {
+		t.Fatalf("❌ Unexpected message %v", msg)
+	}
+
+	conn.WriteJSON(map[string]interface{}{"action": "unsubscribe", "exchange": []string{"usd_eur"}})
+	msg = nil
+	if err := conn.ReadJSON(&msg); err != nil || msg["type"] != "subscribed" || msg["keys"] != nil {
+		t.Errorf("❌ Expected empty subscription, got %v (%v)", msg, err)
+	}
+}
@@ -0,0 +1,289 @@
+// test/integration/task_scheduler_test.go
+package integration
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"service-info/internal/commands"
+	"service-info/internal/cron"
+	"service-info/internal/handlers"
+	"service-info/internal/models"
+	"service-info/internal/repositories"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type capturingSyncProducer struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (p *capturingSyncProducer) Publish(key, value []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = append(p.keys, string(key))
+	return nil
+}
+
+func TestAdminRepository_ClaimDue(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+	repo := repositories.NewAdminRepository(db)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	dueID, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "Moscow"},
+		CreatedAt: now, Schedule: "@every 10m", Enabled: true, NextRunAt: &past,
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	lockedID, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "London"},
+		CreatedAt: now, Schedule: "@every 10m", Enabled: true, NextRunAt: &past,
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	if _, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "Paris"},
+		CreatedAt: now, Schedule: "@every 10m", Enabled: true, NextRunAt: &future,
+	}); err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+
+	// Вторая реплика держит блокировку на одной из задач
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("❌ BeginTx failed: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM scheduled_tasks WHERE id = $1 FOR UPDATE`, lockedID); err != nil {
+		t.Fatalf("❌ Lock failed: %v", err)
+	}
+
+	next := now.Add(10 * time.Minute)
+	claimed, err := repo.ClaimDue(ctx, 0, now, 10, func(models.Task) (*time.Time, error) {
+		return &next, nil
+	})
+	if err != nil {
+		t.Fatalf("❌ ClaimDue failed: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != dueID {
+		t.Fatalf("❌ Expected only task %d (locked one skipped), got %+v", dueID, claimed)
+	}
+
+	task, err := repo.GetByID(ctx, dueID)
+	if err != nil {
+		t.Fatalf("❌ GetByID failed: %v", err)
+	}
+	if task.NextRunAt == nil || !task.NextRunAt.After(now) {
+		t.Fatalf("❌ Expected next_run_at advanced past now, got %v", task.NextRunAt)
+	}
+
+	// Повторный проход до следующего срока ничего не забирает
+	again, err := repo.ClaimDue(ctx, 0, now, 10, func(models.Task) (*time.Time, error) {
+		return &next, nil
+	})
+	if err != nil {
+		t.Fatalf("❌ ClaimDue failed: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("❌ Expected nothing to claim twice, got %+v", again)
+	}
+}
+
+func TestAdminRepository_FencesStaleLeaderTerms(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+	repo := repositories.NewAdminRepository(db)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	id, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "Moscow"},
+		CreatedAt: now, Schedule: "@every 10m", Enabled: true, NextRunAt: &past,
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	next := now.Add(10 * time.Minute)
+	claim := func(term int64) ([]models.Task, error) {
+		return repo.ClaimDue(ctx, term, now, 10, func(models.Task) (*time.Time, error) { return &next, nil })
+	}
+
+	// Новый лидер записал срок 5 — бывший лидер со сроком 4 уже ничего не забирает и не пишет
+	if _, err := claim(5); err != nil {
+		t.Fatalf("❌ ClaimDue(term 5) failed: %v", err)
+	}
+	if err := repo.RecordRun(ctx, 4, id, now, models.TaskStatusPublished, nil); !errors.Is(err, repositories.ErrStaleTerm) {
+		t.Errorf("❌ Expected ErrStaleTerm from RecordRun(term 4), got %v", err)
+	}
+	if task, _ := repo.GetByID(ctx, id); task == nil || task.LastRunAt != nil {
+		t.Errorf("❌ Stale RecordRun must not write, got %+v", task)
+	}
+	if _, err := repo.ClaimDue(ctx, 4, now.Add(time.Hour), 10, func(models.Task) (*time.Time, error) { return &next, nil }); !errors.Is(err, repositories.ErrStaleTerm) {
+		t.Errorf("❌ Expected ErrStaleTerm from ClaimDue(term 4), got %v", err)
+	}
+	if _, err := repositories.NewDigestRepository(db).Claim(ctx, 3, 1, now.Format("2006-01-02")); !errors.Is(err, repositories.ErrStaleTerm) {
+		t.Errorf("❌ Expected ErrStaleTerm from digest Claim(term 3), got %v", err)
+	}
+
+	// Текущий и более новые сроки проходят
+	if err := repo.RecordRun(ctx, 5, id, now, models.TaskStatusPublished, nil); err != nil {
+		t.Errorf("❌ RecordRun(term 5) failed: %v", err)
+	}
+	if _, err := claim(6); err != nil {
+		t.Errorf("❌ ClaimDue(term 6) failed: %v", err)
+	}
+}
+
+func TestTaskScheduler_RunOnce(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+	repo := repositories.NewAdminRepository(db)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+
+	validID, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "Moscow"},
+		CreatedAt: now, Schedule: "@every 10m", Enabled: true, NextRunAt: &past,
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	brokenID, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "London"},
+		CreatedAt: now, Schedule: "not a schedule", Enabled: true, NextRunAt: &past,
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+	unknownID, err := repo.Save(ctx, models.Task{
+		Title: "forecast", Args: models.TaskArgs{"city": "Paris"},
+		CreatedAt: now, Schedule: "@every 10m", Enabled: true, NextRunAt: &past,
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+
+	registry := newTestRegistry(t)
+	producer := &capturingSyncProducer{}
+	scheduler := cron.NewTaskScheduler(repo, registry, producer, nil, time.Minute)
+	if err := scheduler.RunOnce(ctx); err != nil {
+		t.Fatalf("❌ RunOnce failed: %v", err)
+	}
+
+	if len(producer.keys) != 1 {
+		t.Fatalf("❌ Expected exactly 1 published command, got %v", producer.keys)
+	}
+	// Ключ сообщения совпадает с ключом кэша, который обновит воркер
+	cmd, _ := registry.ByType("weather")
+	if want := cmd.CacheKey(models.TaskArgs{"city": "Moscow"}); producer.keys[0] != want {
+		t.Errorf("❌ Expected message key %q, got %q", want, producer.keys[0])
+	}
+
+	valid, err := repo.GetByID(ctx, validID)
+	if err != nil {
+		t.Fatalf("❌ GetByID failed: %v", err)
+	}
+	if valid.LastStatus != models.TaskStatusPublished || valid.LastRunAt == nil || !valid.Enabled {
+		t.Fatalf("❌ Expected valid task published, got %+v", valid)
+	}
+
+	broken, err := repo.GetByID(ctx, brokenID)
+	if err != nil {
+		t.Fatalf("❌ GetByID failed: %v", err)
+	}
+	if broken.Enabled || broken.NextRunAt != nil {
+		t.Fatalf("❌ Expected broken task disabled, got %+v", broken)
+	}
+	if broken.LastStatus != models.TaskStatusFailed || broken.LastError == "" {
+		t.Fatalf("❌ Expected broken task failed with error, got %+v", broken)
+	}
+
+	unknown, err := repo.GetByID(ctx, unknownID)
+	if err != nil {
+		t.Fatalf("❌ GetByID failed: %v", err)
+	}
+	if unknown.LastStatus != models.TaskStatusFailed || unknown.LastError == "" {
+		t.Errorf("❌ Expected task of unregistered type failed, got %+v", unknown)
+	}
+}
+
+func TestAdminHandler_UpdateTask(t *testing.T) {
+	db := testutils.TestDBWithCleanup(t)
+	ctx := context.Background()
+	repo := repositories.NewAdminRepository(db)
+
+	id, err := repo.Save(ctx, models.Task{
+		Title: "weather", Args: models.TaskArgs{"city": "Moscow"}, CreatedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("❌ Save failed: %v", err)
+	}
+
+	registry, err := commands.NewRegistry(services.WeatherCommand, services.ExchangeCommand)
+	if err != nil {
+		t.Fatalf("❌ NewRegistry failed: %v", err)
+	}
+	adminHandler := handlers.NewAdminHandler(services.NewAdminService(repo), registry)
+
+	router := chi.NewRouter()
+	router.Patch("/admin/tasks/{id}", adminHandler.UpdateTask)
+	srv := httptest.NewServer(testutils.WithSpec(t, router))
+	defer srv.Close()
+
+	patch := func(path, body string) int {
+		req, _ := http.NewRequest("PATCH", srv.URL+path, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("❌ PATCH %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	path := "/admin/tasks/" + strconv.Itoa(id)
+	if code := patch(path, `{"schedule":"@every 15m","enabled":true}`); code != http.StatusOK {
+		t.Fatalf("❌ Expected 200, got %d", code)
+	}
+	task, err := repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("❌ GetByID failed: %v", err)
+	}
+	if task.Schedule != "@every 15m" || !task.Enabled || task.NextRunAt == nil {
+		t.Fatalf("❌ Expected schedule applied, got %+v", task)
+	}
+
+	if code := patch(path, `{"schedule":"every sometimes"}`); code != http.StatusBadRequest {
+		t.Fatalf("❌ Expected 400 on invalid schedule, got %d", code)
+	}
+	if code := patch("/admin/tasks/999999", `{"enabled":false}`); code != http.StatusNotFound {
+		t.Fatalf("❌ Expected 404 on missing task, got %d", code)
+	}
+
+	// Невалидный PATCH не затирает сохранённое расписание
+	task, err = repo.GetByID(ctx, id)
+	if err != nil {
+		t.Fatalf("❌ GetByID failed: %v", err)
+	}
+	if task.Schedule != "@every 15m" {
+		t.Fatalf("❌ Expected schedule kept after rejected PATCH, got %q", task.Schedule)
+	}
+}
@@ -0,0 +1,86 @@
+// test/integration/weather_batch_test.go
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-info/internal/api"
+	"service-info/internal/handlers"
+	"service-info/internal/models"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWeatherBatch_PartialFailures(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	// С Redis проверяем и попадание в кэш через MGET
+	cached := "BatchCached"
+	redisUp := rdb.Ping(context.Background()).Err() == nil
+	if redisUp {
+		data, _ := json.Marshal(models.Weather{City: cached, Temp: 7})
+		rdb.Set(context.Background(), services.WeatherFetcher{}.CacheKey(cached), data, time.Minute)
+		defer rdb.Del(context.Background(), services.WeatherFetcher{}.CacheKey(cached))
+	}
+
+	fetcher := &testutils.StubWeatherFetcher{
+		Delay: 50 * time.Millisecond,
+		Errors: map[string]error{
+			"Nowhere": fmt.Errorf("%w: city %q: No matching location found.", api.ErrNotFound, "Nowhere"),
+		},
+	}
+	service := services.NewCacheService[models.Weather](rdb, nil, fetcher)
+	srv := httptest.NewServer(http.HandlerFunc(handlers.NewWeatherHandler(service, nil, nil).GetWeatherBatch))
+	defer srv.Close()
+
+	cities := []string{"Nowhere", "Moscow", "moscow", cached}
+	for i := 0; i < 10; i++ {
+		cities = append(cities, "BatchCity"+string(rune('A'+i)))
+	}
+	body, _ := json.Marshal(map[string][]string{"cities": cities})
+
+	resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("❌ POST /weather/batch failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("❌ Expected 200 on partial failure, got %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Results map[string]models.DashboardItem[models.Weather] `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("❌ JSON decode failed: %v", err)
+	}
+
+	if len(out.Results) != 13 {
+		t.Fatalf("❌ Expected 13 unique cities, got %d: %+v", len(out.Results), out.Results)
+	}
+	if out.Results["Nowhere"].Error != "city not found" || out.Results["Nowhere"].Data != nil {
+		t.Errorf("❌ Expected per-city error for Nowhere, got %+v", out.Results["Nowhere"])
+	}
+	if out.Results["Moscow"].Data == nil || out.Results["BatchCityJ"].Data == nil {
+		t.Errorf("❌ Expected data for other cities, got %+v", out.Results)
+	}
+	if redisUp && (out.Results[cached].Data == nil || out.Results[cached].Data.Temp != 7) {
+		t.Errorf("❌ Expected cached value for %s, got %+v", cached, out.Results[cached])
+	}
+	if peak := fetcher.Peak(); peak > 5 {
+		t.Errorf("❌ Expected at most 5 concurrent fetches, got %d", peak)
+	}
+	if redisUp && fetcher.Calls() != 12 {
+		t.Errorf("❌ Expected 12 upstream fetches, got %d", fetcher.Calls())
+	}
+}
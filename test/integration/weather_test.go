@@ -45,6 +45,12 @@ func TestGetWeather_KafkaToRedis(t *testing.T) {
 		&kafka.KafkaBundle{
 			WeatherConsumer: consumer,
 		},
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 	)
 
 	time.Sleep(1 * time.Second)
@@ -59,7 +65,7 @@ func TestGetWeather_KafkaToRedis(t *testing.T) {
 		services.WeatherFetcher{},
 	)
 
-	weatherHandler := handlers.NewWeatherHandler(weatherService)
+	weatherHandler := handlers.NewWeatherHandler(weatherService, nil, nil)
 
 	router := http.NewServeMux()
 	router.HandleFunc("/weather", weatherHandler.GetWeather)
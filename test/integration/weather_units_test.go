@@ -0,0 +1,65 @@
+// test/integration/weather_units_test.go
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"service-info/internal/handlers"
+	"service-info/internal/models"
+	"service-info/internal/services"
+	testutils "service-info/test/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWeather_LangAndUnits(t *testing.T) {
+	// Redis не нужен: промах кэша уходит в fetcher
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379", DialTimeout: 200 * time.Millisecond})
+	defer rdb.Close()
+
+	fetcher := &testutils.StubWeatherFetcher{Weather: models.Weather{Temp: 20, WindKPH: 16.09344, PressureMB: 1013, VisibilityKM: 10}}
+	handler := handlers.NewWeatherHandler(services.NewCacheService[models.Weather](rdb, nil, fetcher), nil, nil)
+	srv := httptest.NewServer(http.HandlerFunc(handler.GetWeather))
+	defer srv.Close()
+
+	get := func(query string) (int, map[string]any) {
+		resp, err := http.Get(srv.URL + "/weather?" + query)
+		if err != nil {
+			t.Fatalf("❌ GET /weather?%s failed: %v", query, err)
+		}
+		defer resp.Body.Close()
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	status, body := get("city=UnitsTown&units=imperial")
+	if status != http.StatusOK || body["temp_fahrenheit"] != 68.0 || body["wind_mph"] != 10.0 || body["pressure_in"] != 29.91 {
+		t.Errorf("❌ Unexpected imperial weather %d %v", status, body)
+	}
+	if _, ok := body["temp_celsius"]; ok {
+		t.Errorf("❌ Imperial response must not carry metric fields: %v", body)
+	}
+
+	if status, body = get("city=UnitsTown&lang=EN"); status != http.StatusOK || body["temp_celsius"] != 20.0 {
+		t.Errorf("❌ Unexpected metric weather %d %v", status, body)
+	}
+
+	if status, _ = get("city=UnitsTown&units=kelvin"); status != http.StatusBadRequest {
+		t.Errorf("❌ Expected 400 for unknown units, got %d", status)
+	}
+	if status, _ = get("city=UnitsTown&lang=english"); status != http.StatusBadRequest {
+		t.Errorf("❌ Expected 400 for invalid lang, got %d", status)
+	}
+
+	// Язык по умолчанию остаётся в основном ключе, остальные получают свой
+	keys := fetcher.Keys()
+	want := []string{"weather:unitstown", "weather:unitstown:en"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] {
+		t.Errorf("❌ Expected cache keys %v, got %v", want, keys)
+	}
+}
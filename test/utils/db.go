@@ -82,8 +82,109 @@ func setupSchema(db *sql.DB, t *testing.T) {
 			id SERIAL PRIMARY KEY,
 			title TEXT NOT NULL,
 			args JSONB NOT NULL,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			updated_at TIMESTAMPTZ DEFAULT NOW(),
+			schedule TEXT,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			next_run_at TIMESTAMPTZ,
+			last_run_at TIMESTAMPTZ,
+			last_status TEXT,
+			last_error TEXT
+		);
+		DROP TABLE IF EXISTS request_log CASCADE;
+		CREATE TABLE request_log (
+			id BIGSERIAL PRIMARY KEY,
+			type TEXT NOT NULL,
+			args JSONB NOT NULL,
+			user_id BIGINT,
+			created_at TIMESTAMPTZ DEFAULT NOW()
+		);
+		DROP TABLE IF EXISTS user_preferences CASCADE;
+		CREATE TABLE user_preferences (
+			user_id BIGINT PRIMARY KEY,
+			default_city TEXT,
+			favorite_cities JSONB NOT NULL DEFAULT '[]',
+			favorite_pairs JSONB NOT NULL DEFAULT '[]',
+			updated_at TIMESTAMPTZ DEFAULT NOW()
+		);
+		DROP TABLE IF EXISTS exchange_alerts CASCADE;
+		CREATE TABLE exchange_alerts (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			base TEXT NOT NULL,
+			target TEXT NOT NULL,
+			direction TEXT NOT NULL,
+			threshold DOUBLE PRECISION NOT NULL,
+			cooldown_seconds INTEGER NOT NULL DEFAULT 3600,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			last_rate DOUBLE PRECISION,
+			last_triggered_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ DEFAULT NOW()
+		);
+		DROP TABLE IF EXISTS weather_alerts CASCADE;
+		CREATE TABLE weather_alerts (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			city TEXT NOT NULL,
+			metric TEXT NOT NULL,
+			operator TEXT NOT NULL,
+			threshold DOUBLE PRECISION,
+			match TEXT,
+			period_seconds INTEGER NOT NULL DEFAULT 86400,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			last_triggered_at TIMESTAMPTZ,
 			created_at TIMESTAMPTZ DEFAULT NOW()
 		);
+		DROP TABLE IF EXISTS alert_events CASCADE;
+		CREATE TABLE alert_events (
+			id BIGSERIAL PRIMARY KEY,
+			rule_id BIGINT NOT NULL,
+			user_id BIGINT NOT NULL,
+			kind TEXT NOT NULL,
+			message TEXT NOT NULL,
+			payload JSONB NOT NULL DEFAULT '{}',
+			created_at TIMESTAMPTZ DEFAULT NOW()
+		);
+		DROP TABLE IF EXISTS notification_channels CASCADE;
+		CREATE TABLE notification_channels (
+			id BIGSERIAL PRIMARY KEY,
+			user_id BIGINT NOT NULL,
+			channel TEXT NOT NULL,
+			destination TEXT NOT NULL,
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			secret TEXT,
+			UNIQUE (user_id, channel, destination)
+		);
+		DROP TABLE IF EXISTS notification_deliveries CASCADE;
+		CREATE TABLE notification_deliveries (
+			id BIGSERIAL PRIMARY KEY,
+			notification_id TEXT NOT NULL,
+			user_id BIGINT NOT NULL,
+			channel_id BIGINT NOT NULL,
+			channel TEXT NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			created_at TIMESTAMPTZ DEFAULT NOW(),
+			delivered_at TIMESTAMPTZ,
+			UNIQUE (notification_id, channel_id)
+		);
+		DROP TABLE IF EXISTS digest_schedules CASCADE;
+		CREATE TABLE digest_schedules (
+			user_id BIGINT PRIMARY KEY,
+			time_of_day TIME NOT NULL,
+			timezone TEXT NOT NULL DEFAULT 'UTC',
+			enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			last_sent_date DATE,
+			updated_at TIMESTAMPTZ DEFAULT NOW()
+		);
+		DROP TABLE IF EXISTS leader_terms CASCADE;
+		CREATE TABLE leader_terms (
+			name TEXT PRIMARY KEY,
+			term BIGINT NOT NULL,
+			updated_at TIMESTAMPTZ DEFAULT NOW()
+		);
 	`)
 	if err != nil {
 		t.Fatalf("❌ Ошибка создания схемы: %v", err)
@@ -0,0 +1,25 @@
+// test/utils/openapi.go
+package testutils
+
+import (
+	"net/http"
+	"testing"
+
+	"service-info/internal/middleware"
+	"service-info/internal/openapi"
+)
+
+// WithSpec отдаёт handler так, как его видит клиент /v1: с request_id,
+// конвертом ошибок v1 и проверкой запроса и ответа по openapi.yaml.
+// Ответ, не совпавший со спецификацией, валит тест через t.Errorf.
+func WithSpec(t *testing.T, handler http.Handler) http.Handler {
+	t.Helper()
+
+	validator, err := openapi.NewValidator(func(r *http.Request, err error) {
+		t.Errorf("❌ %s %s does not match spec: %v", r.Method, r.URL.Path, err)
+	})
+	if err != nil {
+		t.Fatalf("❌ NewValidator failed: %v", err)
+	}
+	return middleware.RequestID(middleware.APIVersion("v1")(validator.Middleware(handler)))
+}
@@ -0,0 +1,76 @@
+// test/utils/weather.go
+package testutils
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"service-info/internal/models"
+	"service-info/internal/services"
+)
+
+// StubWeatherFetcher подменяет внешнее API погоды в тестах. Отвечает копией
+// Weather с городом из запроса, ошибкой из Errors для отдельных городов,
+// может задерживать ответ на Delay. Считает обращения, пиковое число
+// одновременных запросов и запоминает запрошенные ключи кэша.
+type StubWeatherFetcher struct {
+	Weather models.Weather
+	Delay   time.Duration
+	Errors  map[string]error
+
+	calls, active, peak atomic.Int32
+
+	mu   sync.Mutex
+	keys []string
+}
+
+func (f *StubWeatherFetcher) CacheKey(params ...string) string {
+	return services.WeatherFetcher{}.CacheKey(params...)
+}
+
+func (f *StubWeatherFetcher) Fetch(ctx context.Context, params ...string) (*models.Weather, error) {
+	f.calls.Add(1)
+	n := f.active.Add(1)
+	defer f.active.Add(-1)
+	for {
+		peak := f.peak.Load()
+		if n <= peak || f.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	f.mu.Lock()
+	f.keys = append(f.keys, f.CacheKey(params...))
+	f.mu.Unlock()
+
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+	if err := f.Errors[params[0]]; err != nil {
+		return nil, err
+	}
+
+	weather := f.Weather
+	weather.City = params[0]
+	weather.Updated = time.Now()
+	return &weather, nil
+}
+
+// Calls — сколько раз обращались к fetcher
+func (f *StubWeatherFetcher) Calls() int {
+	return int(f.calls.Load())
+}
+
+// Peak — максимальное число одновременных запросов
+func (f *StubWeatherFetcher) Peak() int {
+	return int(f.peak.Load())
+}
+
+// Keys — ключи кэша в порядке обращений
+func (f *StubWeatherFetcher) Keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.keys...)
+}